@@ -0,0 +1,154 @@
+package gofilepath
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// AppDirs holds the standard per-user directories of an application.
+// Search lists start with the per-user directory, followed by the system
+// directories in order of preference.
+type AppDirs struct {
+	Flavor  Flavor
+	Config  string // user configuration
+	Data    string // user data
+	Cache   string // non-essential cached data
+	State   string // state that persists between runs (logs, history)
+	Runtime string // sockets and pipes; empty if the platform has none set
+
+	ConfigDirs []string // Config followed by system configuration directories
+	DataDirs   []string // Data followed by system data directories
+}
+
+// DirsOptions controls DirsFor.
+type DirsOptions struct {
+	// Flavor selects the layout: XDG Base Directory for FlavorPOSIX,
+	// ~/Library for FlavorDarwin and %APPDATA% for FlavorWindows.
+	Flavor Flavor
+
+	// LookupEnv and HomeDir work like in ExpandOptions; they can be set to
+	// compute the directories of another system or user.
+	LookupEnv func(key string) (string, bool)
+	HomeDir   func(username string) (string, error)
+}
+
+// Dirs returns the standard directories of appName on the running OS.
+//
+//	Dirs("myapp") → Config "/home/me/.config/myapp", Cache "/home/me/.cache/myapp", ...
+func Dirs(appName string) (AppDirs, error) {
+	return DirsFor(appName, DirsOptions{})
+}
+
+// DirsFor returns the standard directories of appName for opts.Flavor.
+// It only computes paths and does not create any directory.
+func DirsFor(appName string, opts DirsOptions) (AppDirs, error) {
+	env := ExpandOptions{LookupEnv: opts.LookupEnv, HomeDir: opts.HomeDir}
+	f := opts.Flavor.Resolve()
+	home, err := env.homeDir("")
+	if err != nil {
+		return AppDirs{}, err
+	}
+	// getDir returns the variable key if it is set to an absolute path,
+	// otherwise def joined to home.
+	getDir := func(key string, def ...string) string {
+		if v, ok := env.lookupEnv(key); ok && v != "" && isAbsFlavor(v, f) {
+			return v
+		}
+		return JoinFlavor(f, append([]string{home}, def...)...)
+	}
+	app := func(dir string, sub ...string) string {
+		if dir == "" {
+			return ""
+		}
+		return JoinFlavor(f, append([]string{dir, appName}, sub...)...)
+	}
+
+	d := AppDirs{Flavor: f}
+	switch f {
+	case FlavorWindows:
+		local := getDir("LOCALAPPDATA", "AppData", "Local")
+		d.Config = app(getDir("APPDATA", "AppData", "Roaming"))
+		d.Data = app(local)
+		d.Cache = app(local, "Cache")
+		d.State = app(local, "State")
+		if tmp, ok := env.lookupEnv("TEMP"); ok && tmp != "" {
+			d.Runtime = app(tmp)
+		}
+		programData := `C:\ProgramData`
+		if v, ok := env.lookupEnv("PROGRAMDATA"); ok && v != "" {
+			programData = v
+		}
+		d.ConfigDirs = []string{d.Config, app(programData)}
+		d.DataDirs = []string{d.Data, app(programData)}
+	case FlavorDarwin:
+		support := JoinFlavor(f, home, "Library", "Application Support")
+		d.Config = app(support)
+		d.Data = app(support)
+		d.Cache = app(JoinFlavor(f, home, "Library", "Caches"))
+		d.State = app(support)
+		if tmp, ok := env.lookupEnv("TMPDIR"); ok && tmp != "" {
+			d.Runtime = app(tmp)
+		}
+		d.ConfigDirs = []string{d.Config, app("/Library/Application Support")}
+		d.DataDirs = []string{d.Data, app("/Library/Application Support")}
+	default:
+		d.Config = app(getDir("XDG_CONFIG_HOME", ".config"))
+		d.Data = app(getDir("XDG_DATA_HOME", ".local", "share"))
+		d.Cache = app(getDir("XDG_CACHE_HOME", ".cache"))
+		d.State = app(getDir("XDG_STATE_HOME", ".local", "state"))
+		if v, ok := env.lookupEnv("XDG_RUNTIME_DIR"); ok && path.IsAbs(v) {
+			d.Runtime = app(v)
+		}
+		d.ConfigDirs = append([]string{d.Config}, xdgSearchDirs(env, "XDG_CONFIG_DIRS", "/etc/xdg", appName)...)
+		d.DataDirs = append([]string{d.Data}, xdgSearchDirs(env, "XDG_DATA_DIRS", "/usr/local/share:/usr/share", appName)...)
+	}
+	return d, nil
+}
+
+// xdgSearchDirs returns the absolute entries of the colon-separated list in
+// key, or of def if key is unset or empty, joined with appName.
+func xdgSearchDirs(env ExpandOptions, key, def, appName string) []string {
+	list, ok := env.lookupEnv(key)
+	if !ok || list == "" {
+		list = def
+	}
+	dirs := []string{}
+	for _, dir := range strings.Split(list, ":") {
+		if path.IsAbs(dir) { // the spec says relative entries are invalid
+			dirs = append(dirs, path.Join(dir, appName))
+		}
+	}
+	return dirs
+}
+
+func isAbsFlavor(p string, f Flavor) bool {
+	if f.Resolve() == FlavorWindows {
+		vol, rest := SplitVolumeFlavor(p, f)
+		return vol != "" && (strings.HasPrefix(vol, "//") || strings.HasPrefix(rest, "/"))
+	}
+	return path.IsAbs(p)
+}
+
+// findIn returns the first existing name below dirs.
+func findIn(dirs []string, f Flavor, name string) (string, error) {
+	for _, dir := range dirs {
+		p := JoinFlavor(f, dir, name)
+		if ok, _ := Exists(p); ok {
+			return p, nil
+		}
+	}
+	return "", &fs.PathError{Op: "find", Path: name, Err: fs.ErrNotExist}
+}
+
+// FindConfigFile returns the first existing name in ConfigDirs, so a user
+// file overrides a system one. name may contain subdirectories. The error
+// wraps fs.ErrNotExist when no directory has it.
+func (d AppDirs) FindConfigFile(name string) (string, error) {
+	return findIn(d.ConfigDirs, d.Flavor, name)
+}
+
+// FindDataFile is like FindConfigFile for DataDirs.
+func (d AppDirs) FindDataFile(name string) (string, error) {
+	return findIn(d.DataDirs, d.Flavor, name)
+}
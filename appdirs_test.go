@@ -0,0 +1,104 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func envFunc(env map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+}
+
+func TestDirsFor(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DirsOptions
+		want AppDirs
+	}{
+		{"xdg defaults", DirsOptions{
+			Flavor:    FlavorPOSIX,
+			LookupEnv: envFunc(map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"}),
+			HomeDir:   func(string) (string, error) { return "/home/me", nil },
+		}, AppDirs{
+			Flavor: FlavorPOSIX, Config: "/home/me/.config/app", Data: "/home/me/.local/share/app",
+			Cache: "/home/me/.cache/app", State: "/home/me/.local/state/app", Runtime: "/run/user/1000/app",
+			ConfigDirs: []string{"/home/me/.config/app", "/etc/xdg/app"},
+			DataDirs:   []string{"/home/me/.local/share/app", "/usr/local/share/app", "/usr/share/app"},
+		}},
+		{"xdg overrides", DirsOptions{
+			Flavor: FlavorPOSIX,
+			LookupEnv: envFunc(map[string]string{
+				"XDG_CONFIG_HOME": "/cfg", "XDG_CACHE_HOME": "relative/ignored",
+				"XDG_CONFIG_DIRS": "/etc/a:rel:/etc/b", "XDG_DATA_DIRS": "/data",
+			}),
+			HomeDir: func(string) (string, error) { return "/home/me", nil },
+		}, AppDirs{
+			Flavor: FlavorPOSIX, Config: "/cfg/app", Data: "/home/me/.local/share/app",
+			Cache: "/home/me/.cache/app", State: "/home/me/.local/state/app",
+			ConfigDirs: []string{"/cfg/app", "/etc/a/app", "/etc/b/app"},
+			DataDirs:   []string{"/home/me/.local/share/app", "/data/app"},
+		}},
+		{"darwin", DirsOptions{
+			Flavor:    FlavorDarwin,
+			LookupEnv: envFunc(nil),
+			HomeDir:   func(string) (string, error) { return "/Users/me", nil },
+		}, AppDirs{
+			Flavor: FlavorDarwin, Config: "/Users/me/Library/Application Support/app", Data: "/Users/me/Library/Application Support/app",
+			Cache: "/Users/me/Library/Caches/app", State: "/Users/me/Library/Application Support/app",
+			ConfigDirs: []string{"/Users/me/Library/Application Support/app", "/Library/Application Support/app"},
+			DataDirs:   []string{"/Users/me/Library/Application Support/app", "/Library/Application Support/app"},
+		}},
+		{"windows", DirsOptions{
+			Flavor:    FlavorWindows,
+			LookupEnv: envFunc(map[string]string{"APPDATA": `C:\Users\me\AppData\Roaming`}),
+			HomeDir:   func(string) (string, error) { return `C:\Users\me`, nil },
+		}, AppDirs{
+			Flavor: FlavorWindows, Config: `C:\Users\me\AppData\Roaming\app`, Data: `C:\Users\me\AppData\Local\app`,
+			Cache: `C:\Users\me\AppData\Local\app\Cache`, State: `C:\Users\me\AppData\Local\app\State`,
+			ConfigDirs: []string{`C:\Users\me\AppData\Roaming\app`, `C:\ProgramData\app`},
+			DataDirs:   []string{`C:\Users\me\AppData\Local\app`, `C:\ProgramData\app`},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := DirsFor("app", tt.opts)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DirsFor = %+v, %v\nwant %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	user := filepath.Join(root, "user")
+	system := filepath.Join(root, "etc")
+	os.MkdirAll(filepath.Join(user, "app"), 0o755)
+	os.MkdirAll(filepath.Join(system, "app"), 0o755)
+	os.WriteFile(filepath.Join(system, "app", "a.conf"), nil, 0o644)
+	os.WriteFile(filepath.Join(system, "app", "b.conf"), nil, 0o644)
+	os.WriteFile(filepath.Join(user, "app", "b.conf"), nil, 0o644)
+
+	d, err := DirsFor("app", DirsOptions{
+		Flavor:    FlavorPOSIX,
+		LookupEnv: envFunc(map[string]string{"XDG_CONFIG_HOME": user, "XDG_CONFIG_DIRS": system}),
+		HomeDir:   func(string) (string, error) { return root, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.FindConfigFile("a.conf"); err != nil || got != filepath.Join(system, "app", "a.conf") {
+		t.Errorf("FindConfigFile(a.conf) = %q, %v", got, err)
+	}
+	if got, err := d.FindConfigFile("b.conf"); err != nil || got != filepath.Join(user, "app", "b.conf") {
+		t.Errorf("FindConfigFile(b.conf) = %q, %v", got, err)
+	}
+	if _, err := d.FindConfigFile("c.conf"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindConfigFile(c.conf) error = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,358 @@
+package gofilepath
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveKind is a supported archive format.
+type archiveKind int
+
+const (
+	notArchive archiveKind = iota
+	archiveZip
+	archiveTar
+	archiveTarGz
+)
+
+// ArchiveOptions controls ArchiveWalker.
+type ArchiveOptions struct {
+	// SniffMagic also recognizes archives by their first bytes, not only
+	// by their extension (.zip, .jar, .tar, .tar.gz, .tgz). It reads the
+	// start of every regular file walked.
+	SniffMagic bool
+}
+
+// SkipArchive is used as a return value from the fs.WalkDirFunc given to
+// an ArchiveWalker, for an archive file, to visit the file but not its
+// members. The finders return it for archives whose members would all be
+// deeper than their maxdeep.
+var SkipArchive = errors.New("skip this archive")
+
+// ArchiveWalkDir walks root like filepath.WalkDir and also descends into
+// the zip and tar archives it finds, see ArchiveWalker.
+func ArchiveWalkDir(root string, fn fs.WalkDirFunc) error {
+	return ArchiveWalker(ArchiveOptions{})(root, fn)
+}
+
+// ArchiveWalker returns a WalkdirFunc that walks like filepath.WalkDir and,
+// after visiting a .zip, .tar or .tar.gz file, visits its members as if
+// the archive were a directory, under virtual paths like
+// "bundle.zip/logs/app.log". Passed to the finders, it applies the same
+// depth and pattern matching to archive members:
+//
+//	FindFilesMatchName("/var/log", "*.log", -1, true, false, ArchiveWalkDir)
+//
+// The archive itself is still visited as a file, before it is opened;
+// returning SkipArchive then leaves it closed. Directories that only
+// appear implicitly in member names are visited too. Returning
+// filepath.SkipDir for a member directory skips its content; an archive
+// that cannot be read is reported to fn a second time with the error,
+// like an unreadable directory. Archives inside archives are not opened.
+// root may be an archive itself.
+//
+// OpenArchivePath opens the virtual paths.
+func ArchiveWalker(opts ArchiveOptions) WalkdirFunc {
+	return func(root string, fn fs.WalkDirFunc) error {
+		return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			kind := notArchive
+			if err == nil && d.Type().IsRegular() {
+				kind = detectArchive(p, opts.SniffMagic)
+			}
+			if kind == notArchive {
+				return fn(p, d, err)
+			}
+			switch err := fn(p, archiveFileEntry{d}, nil); err {
+			case nil:
+			case SkipArchive:
+				return nil
+			default:
+				return err
+			}
+			entries, err := listArchive(p, kind)
+			if err != nil {
+				if err := fn(p, d, err); err != nil && err != filepath.SkipDir {
+					return err
+				}
+				return nil
+			}
+			return walkArchiveEntries(p, entries, fn)
+		})
+	}
+}
+
+// archiveFileEntry marks the DirEntry of an archive file that
+// ArchiveWalker is about to open, so the finders know SkipArchive is
+// understood.
+type archiveFileEntry struct {
+	fs.DirEntry
+}
+
+// isArchiveEntry reports whether d is an archive file visited by an
+// ArchiveWalker.
+func isArchiveEntry(d fs.DirEntry) bool {
+	_, ok := d.(archiveFileEntry)
+	return ok
+}
+
+// archiveEntry is a member of an archive, or a directory implied by the
+// member names.
+type archiveEntry struct {
+	name string // clean slash-separated path inside the archive
+	info fs.FileInfo
+}
+
+// walkArchiveEntries visits the sorted entries below the archive at p.
+func walkArchiveEntries(p string, entries []archiveEntry, fn fs.WalkDirFunc) error {
+	skip := ""
+	for _, e := range entries {
+		if skip != "" && strings.HasPrefix(e.name, skip+"/") {
+			continue
+		}
+		err := fn(filepath.Join(p, filepath.FromSlash(e.name)), fs.FileInfoToDirEntry(e.info), nil)
+		switch {
+		case err == filepath.SkipDir && e.info.IsDir():
+			skip = e.name
+		case err == filepath.SkipDir:
+			// Like WalkDir: skip the remaining entries of the parent.
+			skip = path.Dir(e.name)
+			if skip == "." {
+				return nil
+			}
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// detectArchive returns the format of the file p by its extension, or by
+// its content if sniff is set.
+func detectArchive(p string, sniff bool) archiveKind {
+	lower := strings.ToLower(p)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"):
+		return archiveZip
+	case strings.HasSuffix(lower, ".tar"):
+		return archiveTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return archiveTarGz
+	}
+	if !sniff {
+		return notArchive
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return notArchive
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	return sniffArchive(head[:n], f)
+}
+
+// sniffArchive detects the format from the first bytes of a file. rest
+// continues after head, to look into gzip streams.
+func sniffArchive(head []byte, rest io.Reader) archiveKind {
+	isTar := func(b []byte) bool { return len(b) >= 262 && string(b[257:262]) == "ustar" }
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return archiveZip
+	case isTar(head):
+		return archiveTar
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(io.MultiReader(bytes.NewReader(head), rest))
+		if err != nil {
+			return notArchive
+		}
+		inner := make([]byte, 512)
+		n, _ := io.ReadFull(zr, inner)
+		if isTar(inner[:n]) {
+			return archiveTarGz
+		}
+	}
+	return notArchive
+}
+
+// cleanMemberName returns the path of an archive member relative to the
+// archive, or "" for names that would point outside of it.
+func cleanMemberName(name string) string {
+	name = strings.TrimLeft(strings.ReplaceAll(name, `\`, "/"), "/")
+	name = path.Clean(name)
+	if name == "." || !fs.ValidPath(name) {
+		return ""
+	}
+	return name
+}
+
+// listArchive returns the entries of the archive at p sorted by name,
+// with the directories implied by the member names added.
+func listArchive(p string, kind archiveKind) ([]archiveEntry, error) {
+	byName := map[string]fs.FileInfo{}
+	add := func(name string, info fs.FileInfo) {
+		if name = cleanMemberName(name); name == "" {
+			return
+		}
+		byName[name] = info
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if _, ok := byName[dir]; ok {
+				break
+			}
+			byName[dir] = impliedDir(path.Base(dir))
+		}
+	}
+
+	if kind == archiveZip {
+		zr, err := zip.OpenReader(p)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			add(f.Name, f.FileInfo())
+		}
+	} else {
+		f, tr, err := openTar(p, kind)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			add(hdr.Name, hdr.FileInfo())
+		}
+	}
+
+	entries := make([]archiveEntry, 0, len(byName))
+	for name, info := range byName {
+		entries = append(entries, archiveEntry{name, info})
+	}
+	// Sort like WalkDir visits: a directory's content right after it.
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ReplaceAll(entries[i].name, "/", "\x00") < strings.ReplaceAll(entries[j].name, "/", "\x00")
+	})
+	return entries, nil
+}
+
+func openTar(p string, kind archiveKind) (io.Closer, *tar.Reader, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind == archiveTarGz {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return f, tar.NewReader(zr), nil
+	}
+	return f, tar.NewReader(f), nil
+}
+
+// impliedDir is the FileInfo of a directory that has no entry of its own
+// in an archive.
+type impliedDir string
+
+func (d impliedDir) Name() string       { return string(d) }
+func (d impliedDir) Size() int64        { return 0 }
+func (d impliedDir) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (d impliedDir) ModTime() time.Time { return time.Time{} }
+func (d impliedDir) IsDir() bool        { return true }
+func (d impliedDir) Sys() any           { return nil }
+
+// OpenArchivePath opens a path produced by ArchiveWalker for reading: a
+// regular file, or a member of a zip or tar archive such as
+// "bundle.tar.gz/logs/app.log".
+func OpenArchivePath(p string) (io.ReadCloser, error) {
+	p = FromSlash(p)
+	if fi, err := os.Stat(p); err == nil {
+		if fi.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: p, Err: errors.New("is a directory")}
+		}
+		return os.Open(p)
+	}
+	// Find the archive among the parents of p.
+	for archive := filepath.Dir(p); archive != filepath.Dir(archive); archive = filepath.Dir(archive) {
+		fi, err := os.Stat(archive)
+		if err != nil {
+			continue
+		}
+		kind := notArchive
+		if fi.Mode().IsRegular() {
+			kind = detectArchive(archive, true)
+		}
+		if kind == notArchive {
+			break
+		}
+		rel, err := filepath.Rel(archive, p)
+		if err != nil {
+			break
+		}
+		return openArchiveMember(archive, kind, filepath.ToSlash(rel))
+	}
+	return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrNotExist}
+}
+
+func openArchiveMember(archive string, kind archiveKind, member string) (io.ReadCloser, error) {
+	notFound := &fs.PathError{Op: "open", Path: filepath.Join(archive, filepath.FromSlash(member)), Err: fs.ErrNotExist}
+	if kind == archiveZip {
+		zr, err := zip.OpenReader(archive)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if cleanMemberName(f.Name) == member && !f.FileInfo().IsDir() {
+				rc, err := f.Open()
+				if err != nil {
+					zr.Close()
+					return nil, err
+				}
+				return readCloser{rc, func() error { rc.Close(); return zr.Close() }}, nil
+			}
+		}
+		zr.Close()
+		return nil, notFound
+	}
+
+	f, tr, err := openTar(archive, kind)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			f.Close()
+			if err == io.EOF {
+				return nil, notFound
+			}
+			return nil, err
+		}
+		if cleanMemberName(hdr.Name) == member && hdr.Typeflag != tar.TypeDir {
+			return readCloser{tr, f.Close}, nil
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error { return r.close() }
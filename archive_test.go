@@ -0,0 +1,173 @@
+package gofilepath
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func writeZip(t *testing.T, p string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGz(t *testing.T, p string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	tw := tar.NewWriter(zw)
+	for name, data := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		io.WriteString(tw, data)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveWalkDir(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"plain.log": "plain"})
+	writeZip(t, filepath.Join(root, "bundle.zip"), map[string]string{
+		"app.log":         "zipped",
+		"logs/deep/x.log": "deep",
+		"../escape.log":   "outside", // never listed
+		"logs/readme.txt": "text",
+	})
+	os.Mkdir(filepath.Join(root, "sub"), 0o755)
+	writeTarGz(t, filepath.Join(root, "sub", "backup.tar.gz"), map[string]string{"var/db.log": "tarred"})
+	writeZip(t, filepath.Join(root, "disguised.bin"), map[string]string{"hidden.log": "sniffed"})
+
+	rel := func(paths []string) []string {
+		var out []string
+		for _, p := range paths {
+			r, _ := filepath.Rel(root, p)
+			out = append(out, filepath.ToSlash(r))
+		}
+		sort.Strings(out)
+		return out
+	}
+	got := rel(FindFilesMatchName(root, "*.log", -1, true, false, ArchiveWalkDir))
+	want := []string{"bundle.zip/app.log", "bundle.zip/logs/deep/x.log", "plain.log", "sub/backup.tar.gz/var/db.log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindFilesMatchName = %v, want %v", got, want)
+	}
+
+	// Depth counts the components inside archives like directories.
+	got = rel(FindFilesMatchName(root, "*.log", 1, true, false, ArchiveWalkDir))
+	want = []string{"bundle.zip/app.log", "plain.log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindFilesMatchName depth 1 = %v, want %v", got, want)
+	}
+
+	got = rel(FindFilesMatchName(root, "hidden.log", -1, true, false, ArchiveWalker(ArchiveOptions{SniffMagic: true})))
+	if want := []string{"disguised.bin/hidden.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sniffed = %v, want %v", got, want)
+	}
+
+	got = rel(FindFilesMatchName(root, "deep", -1, false, true, ArchiveWalkDir))
+	if want := []string{"bundle.zip/logs/deep"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("implied directories = %v, want %v", got, want)
+	}
+}
+
+func TestArchiveWalkDirDepth(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"sub/broken.zip": "not a zip"})
+	bundle := filepath.Join(root, "bundle.zip")
+	writeZip(t, bundle, map[string]string{"app.log": "a", "logs/deep/x.log": "x"})
+
+	// The walker reports an archive it failed to read; record which ones
+	// the finder made it open.
+	var opened []string
+	walker := func(root string, fn fs.WalkDirFunc) error {
+		return ArchiveWalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				opened = append(opened, filepath.Base(p))
+			}
+			return fn(p, d, err)
+		})
+	}
+	FindFilesMatchName(root, "*.log", 1, true, false, walker)
+	if len(opened) != 0 {
+		t.Errorf("depth 1 opened %v", opened)
+	}
+	FindFilesMatchName(root, "*.log", 2, true, false, walker)
+	if want := []string{"broken.zip"}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("depth 2 opened %v, want %v", opened, want)
+	}
+
+	// An archive as the root is searched like a directory.
+	for maxdeep, want := range map[int][]string{
+		-1: {bundle, filepath.Join(bundle, "app.log"), filepath.Join(bundle, "logs", "deep", "x.log")},
+		0:  {bundle, filepath.Join(bundle, "app.log")},
+	} {
+		got := FindFilesMatchName(bundle, "*", maxdeep, true, false, ArchiveWalkDir)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FindFilesMatchName(bundle.zip, %d) = %v, want %v", maxdeep, got, want)
+		}
+	}
+	if got := FindFilesMatchName(bundle, "*.log", -1, true, false); len(got) != 0 {
+		t.Errorf("without ArchiveWalkDir = %v", got)
+	}
+}
+
+func TestOpenArchivePath(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"plain.txt": "plain"})
+	writeZip(t, filepath.Join(root, "a.zip"), map[string]string{"dir/f.txt": "zipped"})
+	writeTarGz(t, filepath.Join(root, "b.tgz"), map[string]string{"./g.txt": "tarred"})
+
+	for p, want := range map[string]string{
+		"plain.txt":       "plain",
+		"a.zip/dir/f.txt": "zipped",
+		"b.tgz/g.txt":     "tarred",
+	} {
+		rc, err := OpenArchivePath(filepath.Join(root, p))
+		if err != nil {
+			t.Errorf("OpenArchivePath(%s): %v", p, err)
+			continue
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || string(b) != want {
+			t.Errorf("OpenArchivePath(%s) read %q, %v; want %q", p, b, err, want)
+		}
+	}
+	for _, p := range []string{"a.zip/missing", "a.zip/dir", "nothere/x"} {
+		if rc, err := OpenArchivePath(filepath.Join(root, p)); err == nil {
+			rc.Close()
+			t.Errorf("OpenArchivePath(%s) succeeded", p)
+		}
+	}
+}
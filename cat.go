@@ -0,0 +1,214 @@
+package gofilepath
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// CatOptions controls how CatTo concatenates files.
+type CatOptions struct {
+	// Separator is written between two consecutive files. Empty means the
+	// files are concatenated back to back like cat(1).
+	Separator string
+
+	// Header, if not nil, returns a string written before each file,
+	// e.g. func(name string) string { return "==> " + name + " <==\n" }.
+	Header func(name string) string
+
+	// MaxBytes limits the total number of bytes written to w, separators
+	// and headers included. Zero or negative means unlimited.
+	MaxBytes int64
+
+	// SkipMissing skips files that do not exist instead of failing.
+	// Skipped files are reported in CatResult.Missing.
+	SkipMissing bool
+
+	// Decompress transparently decompresses files ending in ".gz" or ".zst".
+	Decompress bool
+
+	// Glob expands arguments containing glob meta characters with
+	// filepath.Glob. A pattern without matches is treated as a missing file.
+	Glob bool
+}
+
+// CatResult reports what CatTo did.
+type CatResult struct {
+	Written   int64    // bytes written to w
+	Files     []string // files that were written, in order
+	Missing   []string // files skipped because they do not exist
+	Truncated bool     // output was cut at MaxBytes
+}
+
+// errCatLimit stops copying once MaxBytes is reached.
+var errCatLimit = errors.New("gofilepath: cat limit reached")
+
+// limitedWriter writes at most n bytes to w and then fails with errCatLimit.
+type limitedWriter struct {
+	w io.Writer
+	n int64 // remaining bytes, < 0 means unlimited
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n < 0 {
+		return l.w.Write(p)
+	}
+	if l.n == 0 {
+		return 0, errCatLimit
+	}
+	short := false
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+		short = true
+	}
+	n, err := l.w.Write(p)
+	l.n -= int64(n)
+	if err == nil && short {
+		err = errCatLimit
+	}
+	return n, err
+}
+
+// expandCatArgs applies glob expansion to files when enabled. Patterns
+// without matches are returned as-is so they are reported as missing.
+func expandCatArgs(files []string, glob bool) ([]string, error) {
+	if !glob {
+		return files, nil
+	}
+	expanded := make([]string, 0, len(files))
+	for _, f := range files {
+		if !strings.ContainsAny(f, "*?[") {
+			expanded = append(expanded, f)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.FromSlash(f))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			expanded = append(expanded, f)
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded, nil
+}
+
+// openCatFile opens name and, if decompress is set, wraps it in a
+// decompressor chosen by extension.
+func openCatFile(name string, decompress bool) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if !decompress {
+		return f, nil
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz":
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &catReadCloser{Reader: zr, closers: []io.Closer{zr, f}}, nil
+	case ".zst":
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		rc := zr.IOReadCloser()
+		return &catReadCloser{Reader: rc, closers: []io.Closer{rc, f}}, nil
+	}
+	return f, nil
+}
+
+type catReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (c *catReadCloser) Close() (err error) {
+	for _, cl := range c.closers {
+		if e := cl.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// CatTo streams the contents of files to w without loading them into memory.
+// Files are written in order, with the optional header and separator from
+// opts. When opts.MaxBytes is reached CatTo stops and sets Truncated; this is
+// not an error.
+//
+// Unless opts.SkipMissing is set, CatTo stops at the first file that does not
+// exist and returns its error. Any other open or read error always stops CatTo.
+func CatTo(w io.Writer, files []string, opts CatOptions) (res CatResult, err error) {
+	files, err = expandCatArgs(files, opts.Glob)
+	if err != nil {
+		return
+	}
+	lw := &limitedWriter{w: w, n: -1}
+	if opts.MaxBytes > 0 {
+		lw.n = opts.MaxBytes
+	}
+	cw := &countWriter{w: lw}
+	defer func() {
+		res.Written = cw.n
+		if errors.Is(err, errCatLimit) {
+			res.Truncated = true
+			err = nil
+		}
+	}()
+
+	for _, name := range files {
+		var rc io.ReadCloser
+		rc, err = openCatFile(name, opts.Decompress)
+		if err != nil {
+			if opts.SkipMissing && errors.Is(err, fs.ErrNotExist) {
+				res.Missing = append(res.Missing, name)
+				err = nil
+				continue
+			}
+			return
+		}
+		if len(res.Files) != 0 && opts.Separator != "" {
+			if _, err = io.WriteString(cw, opts.Separator); err != nil {
+				rc.Close()
+				return
+			}
+		}
+		if opts.Header != nil {
+			if _, err = io.WriteString(cw, opts.Header(name)); err != nil {
+				rc.Close()
+				return
+			}
+		}
+		res.Files = append(res.Files, name)
+		_, err = io.Copy(cw, rc)
+		rc.Close()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,62 @@
+package gofilepath
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCatTo(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	gz := filepath.Join(dir, "c.log.gz")
+	os.WriteFile(a, []byte("aaa"), 0o644)
+	os.WriteFile(b, []byte("bbb"), 0o644)
+	var zbuf bytes.Buffer
+	zw := gzip.NewWriter(&zbuf)
+	zw.Write([]byte("ccc"))
+	zw.Close()
+	os.WriteFile(gz, zbuf.Bytes(), 0o644)
+
+	tests := []struct {
+		name      string
+		files     []string
+		opts      CatOptions
+		want      string
+		missing   int
+		truncated bool
+		wantErr   bool
+	}{
+		{"plain", []string{a, b}, CatOptions{}, "aaabbb", 0, false, false},
+		{"separator", []string{a, b}, CatOptions{Separator: "\n"}, "aaa\nbbb", 0, false, false},
+		{"header", []string{a}, CatOptions{Header: func(n string) string { return "# " + filepath.Base(n) + "\n" }}, "# a.txt\naaa", 0, false, false},
+		{"limit", []string{a, b}, CatOptions{MaxBytes: 4}, "aaab", 0, true, false},
+		{"missing", []string{a, filepath.Join(dir, "nope"), b}, CatOptions{SkipMissing: true}, "aaabbb", 1, false, false},
+		{"missing fails", []string{a, filepath.Join(dir, "nope")}, CatOptions{}, "aaa", 0, false, true},
+		{"gzip", []string{gz}, CatOptions{Decompress: true}, "ccc", 0, false, false},
+		{"glob", []string{filepath.Join(dir, "*.txt")}, CatOptions{Glob: true}, "aaabbb", 0, false, false},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		res, err := CatTo(&buf, tt.files, tt.opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: CatTo error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: CatTo wrote %q, want %q", tt.name, got, tt.want)
+		}
+		if res.Written != int64(buf.Len()) {
+			t.Errorf("%s: Written = %d, want %d", tt.name, res.Written, buf.Len())
+		}
+		if len(res.Missing) != tt.missing {
+			t.Errorf("%s: Missing = %v, want %d entries", tt.name, res.Missing, tt.missing)
+		}
+		if res.Truncated != tt.truncated {
+			t.Errorf("%s: Truncated = %v, want %v", tt.name, res.Truncated, tt.truncated)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gofilepath
+
+import (
+	"path"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PathKeyOptions selects how paths are canonicalized for comparison.
+type PathKeyOptions struct {
+	Flavor    Flavor // separator and volume syntax
+	FoldCase  bool   // compare case-insensitively
+	Normalize bool   // compare Unicode names in NFC, so NFC and NFD forms match
+}
+
+// KeyOptions returns the comparison rules of the default filesystems of f:
+// Windows folds case, macOS folds case and normalizes Unicode, POSIX
+// compares bytes.
+func (f Flavor) KeyOptions() PathKeyOptions {
+	switch f = f.Resolve(); f {
+	case FlavorWindows:
+		return PathKeyOptions{Flavor: f, FoldCase: true}
+	case FlavorDarwin:
+		return PathKeyOptions{Flavor: f, FoldCase: true, Normalize: true}
+	}
+	return PathKeyOptions{Flavor: f}
+}
+
+// Key returns a canonical form of p: cleaned, with '/' separators for
+// FlavorWindows, case-folded and normalized as selected. Two paths naming
+// the same entry under these rules have the same key, so it is suitable as
+// a map key. The key is meant for comparison, not for opening files.
+func (o PathKeyOptions) Key(p string) string {
+	if o.Flavor.Resolve() == FlavorWindows {
+		p = NormalizeSeparators(p)
+	}
+	if p != "" {
+		unc := strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "///")
+		p = path.Clean(p)
+		if unc && o.Flavor.Resolve() == FlavorWindows {
+			p = "/" + p // path.Clean collapses the UNC prefix
+		}
+	}
+	if o.Normalize {
+		p = norm.NFC.String(p)
+	}
+	if o.FoldCase {
+		p = strings.ToLower(p)
+	}
+	return p
+}
+
+// Equal reports whether a and b have the same key.
+func (o PathKeyOptions) Equal(a, b string) bool {
+	return o.Key(a) == o.Key(b)
+}
+
+// CanonicalKey returns the comparison key of p under the default rules of
+// flavor, see Flavor.KeyOptions and PathKeyOptions.Key.
+//
+//	CanonicalKey(`C:\Users\`, FlavorWindows) → "c:/users"
+func CanonicalKey(p string, flavor Flavor) string {
+	return flavor.KeyOptions().Key(p)
+}
+
+// EqualPaths reports whether a and b name the same path under the default
+// rules of flavor, without touching the filesystem.
+//
+//	EqualPaths(`C:\Users`, "c:/users/", FlavorWindows) → true
+func EqualPaths(a, b string, flavor Flavor) bool {
+	return flavor.KeyOptions().Equal(a, b)
+}
+
+// driveLetter returns the "X:" prefix of a Windows drive path, or "".
+func driveLetter(p string) string {
+	if len(p) >= 2 && p[1] == ':' {
+		if c := p[0]; (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
+			return p[:2]
+		}
+	}
+	return ""
+}
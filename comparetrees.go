@@ -0,0 +1,313 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DiffReason is a set of ways two entries differ.
+type DiffReason int
+
+const (
+	DiffType    DiffReason = 1 << iota // file, directory, symlink, ...
+	DiffSize                           // size of regular files
+	DiffMode                           // permission bits
+	DiffContent                        // content hash of regular files of the same size
+	DiffTarget                         // symlink target
+)
+
+func (r DiffReason) String() string {
+	names := []string{}
+	for i, n := range []string{"type", "size", "mode", "content", "target"} {
+		if r&(1<<i) != 0 {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// CompareOptions controls CompareTrees.
+type CompareOptions struct {
+	// Filter selects the compared entries by their path relative to the
+	// roots. Include applies to files only; directories are compared
+	// unless they match Exclude.
+	Filter Filter
+
+	// MaxDepth limits the comparison like the maxdeep argument of the
+	// finders: 0 compares only the entries directly in the roots.
+	// Negative means unlimited.
+	MaxDepth int
+
+	// Symlinks selects how links are compared: SymlinkCopy compares their
+	// targets, SymlinkFollow compares what they point to and SymlinkSkip
+	// ignores them, unless the other tree has another type of entry
+	// there, which is a DiffType.
+	Symlinks SymlinkPolicy
+
+	// IgnoreMode does not compare permission bits.
+	IgnoreMode bool
+
+	// Hash compares the content of regular files of the same size. Nil
+	// means SHA256.
+	Hash HashAlgorithm
+}
+
+// TreeDifference is an entry present in both trees that differs.
+type TreeDifference struct {
+	Path   string // relative to the roots, with '/' separators
+	Reason DiffReason
+	A, B   fs.FileInfo
+}
+
+// TreeComparison is the result of CompareTrees. All paths are relative to
+// the roots, with '/' separators, and sorted. A directory present in only
+// one tree is listed without its content.
+type TreeComparison struct {
+	A, B    string // the compared roots
+	OnlyInA []string
+	OnlyInB []string
+	Differ  []TreeDifference
+}
+
+// Equal reports whether no difference was found.
+func (c *TreeComparison) Equal() bool {
+	return len(c.OnlyInA)+len(c.OnlyInB)+len(c.Differ) == 0
+}
+
+// WriteReport writes the differences in the format of diff -rq:
+//
+//	Only in a/dir: name
+//	Files a/x and b/x differ
+//	File a/y is a regular file while file b/y is a directory
+//
+// Differences diff does not report are described the same way, e.g.
+// "Modes of a/x and b/x differ (-rw-r--r-- vs -rwxr-xr-x)".
+func (c *TreeComparison) WriteReport(w io.Writer) error {
+	type line struct{ path, text string }
+	var lines []line
+	only := func(root string, paths []string) {
+		for _, p := range paths {
+			dir, name := filepath.Split(filepath.Join(root, filepath.FromSlash(p)))
+			lines = append(lines, line{p, fmt.Sprintf("Only in %s: %s", filepath.Clean(dir), name)})
+		}
+	}
+	only(c.A, c.OnlyInA)
+	only(c.B, c.OnlyInB)
+	for _, d := range c.Differ {
+		a := filepath.Join(c.A, filepath.FromSlash(d.Path))
+		b := filepath.Join(c.B, filepath.FromSlash(d.Path))
+		var text string
+		switch {
+		case d.Reason&DiffType != 0:
+			text = fmt.Sprintf("File %s is a %s while file %s is a %s", a, describeType(d.A), b, describeType(d.B))
+		case d.Reason&DiffTarget != 0:
+			text = fmt.Sprintf("Symbolic links %s and %s differ", a, b)
+		case d.Reason&(DiffSize|DiffContent) != 0:
+			text = fmt.Sprintf("Files %s and %s differ", a, b)
+		default:
+			text = fmt.Sprintf("Modes of %s and %s differ (%v vs %v)", a, b, d.A.Mode().Perm(), d.B.Mode().Perm())
+		}
+		lines = append(lines, line{d.Path, text})
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].path < lines[j].path })
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, l.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeType names the type of fi like diff does.
+func describeType(fi fs.FileInfo) string {
+	switch KindOf(fi.Mode()) {
+	case KindFile:
+		if fi.Size() == 0 {
+			return "regular empty file"
+		}
+		return "regular file"
+	case KindDir:
+		return "directory"
+	case KindSymlink:
+		return "symbolic link"
+	case KindSocket:
+		return "socket"
+	case KindFifo:
+		return "fifo"
+	case KindDevice:
+		if fi.Mode()&fs.ModeCharDevice != 0 {
+			return "character special file"
+		}
+		return "block special file"
+	}
+	return "weird file"
+}
+
+// CompareTrees compares the trees a and b entry by entry, like diff -rq.
+// Regular files of the same size are compared by content hash. The first
+// error reading either tree stops the comparison.
+func CompareTrees(a, b string, opts CompareOptions) (*TreeComparison, error) {
+	tc := &treeComparer{opts: opts, res: &TreeComparison{A: FromSlash(a), B: FromSlash(b)}}
+	fa, err := tc.stat(tc.res.A)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := tc.stat(tc.res.B)
+	if err != nil {
+		return nil, err
+	}
+	if !fa.IsDir() || !fb.IsDir() {
+		return nil, errors.New("compare: " + a + " and " + b + " must be directories")
+	}
+	if err := tc.compareDir("", fa, fb, nil); err != nil {
+		return nil, err
+	}
+	sort.Strings(tc.res.OnlyInA)
+	sort.Strings(tc.res.OnlyInB)
+	sort.Slice(tc.res.Differ, func(i, j int) bool { return tc.res.Differ[i].Path < tc.res.Differ[j].Path })
+	return tc.res, nil
+}
+
+type treeComparer struct {
+	opts CompareOptions
+	res  *TreeComparison
+}
+
+func (tc *treeComparer) stat(path string) (fs.FileInfo, error) {
+	if tc.opts.Symlinks == SymlinkFollow {
+		return os.Stat(path)
+	}
+	return os.Lstat(path)
+}
+
+// compareDir compares the directory rel of both trees. ancestors holds the
+// directories of tree a above rel, to detect loops when following links.
+func (tc *treeComparer) compareDir(rel string, fa, fb fs.FileInfo, ancestors []fs.FileInfo) error {
+	for _, anc := range ancestors {
+		if os.SameFile(anc, fa) {
+			return &fs.PathError{Op: "compare", Path: filepath.Join(tc.res.A, rel), Err: errors.New("symlink loop")}
+		}
+	}
+	ancestors = append(ancestors, fa)
+	names := map[string]int{} // 1: in a, 2: in b, 3: in both
+	for i, root := range []string{tc.res.A, tc.res.B} {
+		entries, err := os.ReadDir(filepath.Join(root, rel))
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			names[e.Name()] |= 1 << i
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		childRel := filepath.Join(rel, name)
+		depth := strings.Count(childRel, string(filepath.Separator))
+		if tc.opts.MaxDepth >= 0 && depth > tc.opts.MaxDepth {
+			continue
+		}
+		var infos [2]fs.FileInfo
+		present, links := 0, 0
+		for i, root := range []string{tc.res.A, tc.res.B} {
+			if names[name]&(1<<i) == 0 {
+				continue
+			}
+			fi, err := tc.stat(filepath.Join(root, childRel))
+			if err != nil {
+				return err
+			}
+			present++
+			if fi.Mode()&fs.ModeSymlink != 0 {
+				links++
+			}
+			infos[i] = fi
+		}
+		// Skipped links are left out only where no other type faces them.
+		if tc.opts.Symlinks == SymlinkSkip && links == present {
+			continue
+		}
+		isDir := infos[0] != nil && infos[0].IsDir() || infos[1] != nil && infos[1].IsDir()
+		if isDir && tc.opts.Filter.Excluded(childRel) || !isDir && !tc.opts.Filter.Match(childRel) {
+			continue
+		}
+
+		slashRel := filepath.ToSlash(childRel)
+		switch {
+		case infos[0] == nil && infos[1] == nil:
+		case infos[1] == nil:
+			tc.res.OnlyInA = append(tc.res.OnlyInA, slashRel)
+		case infos[0] == nil:
+			tc.res.OnlyInB = append(tc.res.OnlyInB, slashRel)
+		default:
+			reason, err := tc.compareEntry(childRel, infos[0], infos[1])
+			if err != nil {
+				return err
+			}
+			if reason != 0 {
+				tc.res.Differ = append(tc.res.Differ, TreeDifference{Path: slashRel, Reason: reason, A: infos[0], B: infos[1]})
+			}
+			// Directories at MaxDepth are compared, not their content.
+			if reason&DiffType == 0 && infos[0].IsDir() && depth != tc.opts.MaxDepth {
+				if err := tc.compareDir(childRel, infos[0], infos[1], ancestors); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compareEntry compares two entries found under the same relative path.
+func (tc *treeComparer) compareEntry(rel string, fa, fb fs.FileInfo) (DiffReason, error) {
+	if fa.Mode().Type() != fb.Mode().Type() {
+		return DiffType, nil
+	}
+	var reason DiffReason
+	if !tc.opts.IgnoreMode && fa.Mode().Perm() != fb.Mode().Perm() && fa.Mode()&fs.ModeSymlink == 0 {
+		reason |= DiffMode
+	}
+	switch {
+	case fa.Mode()&fs.ModeSymlink != 0:
+		ta, err := os.Readlink(filepath.Join(tc.res.A, rel))
+		if err != nil {
+			return 0, err
+		}
+		tb, err := os.Readlink(filepath.Join(tc.res.B, rel))
+		if err != nil {
+			return 0, err
+		}
+		if ta != tb {
+			reason |= DiffTarget
+		}
+	case fa.Mode().IsRegular():
+		if fa.Size() != fb.Size() {
+			return reason | DiffSize, nil
+		}
+		ha, err := HashFile(filepath.Join(tc.res.A, rel), tc.opts.Hash)
+		if err != nil {
+			return 0, err
+		}
+		hb, err := HashFile(filepath.Join(tc.res.B, rel), tc.opts.Hash)
+		if err != nil {
+			return 0, err
+		}
+		if ha != hb {
+			reason |= DiffContent
+		}
+	}
+	return reason, nil
+}
@@ -0,0 +1,123 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareTrees(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	writeTree(t, a, map[string]string{
+		"same.txt":      "same",
+		"content.txt":   "aaaa",
+		"size.txt":      "short",
+		"mode.sh":       "#!/bin/sh",
+		"onlya/f":       "x",
+		"kind":          "file",
+		"sub/deep.txt":  "deep a",
+		"ignored.log":   "a",
+		"skip/file.txt": "a",
+	})
+	writeTree(t, b, map[string]string{
+		"same.txt":     "same",
+		"content.txt":  "bbbb",
+		"size.txt":     "much longer",
+		"mode.sh":      "#!/bin/sh",
+		"onlyb.txt":    "y",
+		"kind/f":       "dir",
+		"sub/deep.txt": "deep b",
+		"ignored.log":  "b",
+	})
+	os.Chmod(filepath.Join(b, "mode.sh"), 0o755)
+	os.Symlink("same.txt", filepath.Join(a, "link"))
+	os.Symlink("content.txt", filepath.Join(b, "link"))
+
+	res, err := CompareTrees(a, b, CompareOptions{
+		MaxDepth: -1,
+		Filter:   Filter{Exclude: []string{"*.log", "skip"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := res.WriteReport(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"Files " + a + "/content.txt and " + b + "/content.txt differ",
+		"File " + a + "/kind is a regular file while file " + b + "/kind is a directory",
+		"Symbolic links " + a + "/link and " + b + "/link differ",
+		"Modes of " + a + "/mode.sh and " + b + "/mode.sh differ (-rw-r--r-- vs -rwxr-xr-x)",
+		"Only in " + a + ": onlya",
+		"Only in " + b + ": onlyb.txt",
+		"Files " + a + "/size.txt and " + b + "/size.txt differ",
+		"Files " + a + "/sub/deep.txt and " + b + "/sub/deep.txt differ",
+	}, "\n") + "\n"
+	if got := sb.String(); got != want {
+		t.Errorf("report =\n%s\nwant\n%s", got, want)
+	}
+	if res.Equal() {
+		t.Error("Equal() = true")
+	}
+
+	// Following links compares the files they point to.
+	res, err = CompareTrees(a, b, CompareOptions{MaxDepth: 0, Symlinks: SymlinkFollow, IgnoreMode: true, Filter: Filter{Include: []string{"link", "same.txt"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// kind is still compared: Include does not apply to directories.
+	if len(res.Differ) != 2 || res.Differ[1].Path != "link" || res.Differ[1].Reason != DiffContent {
+		t.Errorf("followed links: %+v", res.Differ)
+	}
+	if res, err := CompareTrees(a, a, CompareOptions{MaxDepth: -1}); err != nil || !res.Equal() {
+		t.Errorf("tree differs from itself: %+v, %v", res, err)
+	}
+}
+
+func TestCompareTreesSkippedLinks(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	writeTree(t, a, map[string]string{"f": "x"})
+	writeTree(t, b, map[string]string{"f": "x", "file": "x"})
+	os.Symlink("f", filepath.Join(a, "file"))
+	os.Symlink("f", filepath.Join(a, "onlya"))
+	os.Symlink("f", filepath.Join(a, "both"))
+	os.Symlink("x", filepath.Join(b, "both"))
+
+	res, err := CompareTrees(a, b, CompareOptions{MaxDepth: -1, Symlinks: SymlinkSkip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.OnlyInA)+len(res.OnlyInB) != 0 || len(res.Differ) != 1 || res.Differ[0].Path != "file" || res.Differ[0].Reason != DiffType {
+		t.Errorf("SymlinkSkip: only in a %v, only in b %v, differ %+v", res.OnlyInA, res.OnlyInB, res.Differ)
+	}
+}
+
+func TestCompareTreesStopsAtMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	writeTree(t, a, map[string]string{"locked/f": "a"})
+	writeTree(t, b, map[string]string{"locked/f": "b"})
+	locked := filepath.Join(a, "locked")
+	for _, d := range []string{locked, filepath.Join(b, "locked")} {
+		os.Chmod(d, 0o300)
+		defer os.Chmod(d, 0o755)
+	}
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("running with permissions that ignore directory modes")
+	}
+
+	res, err := CompareTrees(a, b, CompareOptions{MaxDepth: 0})
+	if err != nil || !res.Equal() {
+		t.Errorf("MaxDepth 0: %+v, %v", res, err)
+	}
+	if _, err := CompareTrees(a, b, CompareOptions{MaxDepth: 1}); err == nil {
+		t.Error("MaxDepth 1 read the unreadable directory")
+	}
+}
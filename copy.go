@@ -0,0 +1,392 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrDestinationExists is returned by CopyTree with ConflictError when a
+// destination entry already exists.
+var ErrDestinationExists = errors.New("destination exists")
+
+// SymlinkPolicy selects how CopyTree handles symbolic links.
+type SymlinkPolicy int
+
+const (
+	SymlinkCopy   SymlinkPolicy = iota // recreate the link with the same target
+	SymlinkFollow                      // copy what the link points to, or the link if it dangles
+	SymlinkSkip                        // leave links out
+)
+
+// ConflictPolicy selects what CopyTree does when a destination entry
+// exists. Existing directories are always merged into.
+type ConflictPolicy int
+
+const (
+	ConflictError     ConflictPolicy = iota // fail with ErrDestinationExists
+	ConflictOverwrite                       // replace the existing entry
+	ConflictSkip                            // keep the existing entry
+	ConflictRename                          // copy to "name (1).ext", "name (2).ext", ...
+)
+
+// PreserveFlags selects the metadata CopyTree copies besides the
+// permission bits, which are always copied.
+type PreserveFlags int
+
+const (
+	PreserveMode   PreserveFlags = 1 << iota // setuid, setgid and sticky bits
+	PreserveTimes                            // modification times
+	PreserveOwner                            // user and group, where permitted
+	PreserveXattrs                           // extended attributes (Linux)
+
+	PreserveAll = PreserveMode | PreserveTimes | PreserveOwner | PreserveXattrs
+)
+
+// CopyOptions controls CopyTree.
+type CopyOptions struct {
+	Symlinks SymlinkPolicy
+	Conflict ConflictPolicy
+	Preserve PreserveFlags
+
+	// Filter selects the copied entries by their path relative to src.
+	// Include applies to files only, so directories are always created
+	// unless they match Exclude.
+	Filter Filter
+
+	// Progress, if not nil, is called while files are copied.
+	Progress func(CopyProgress)
+}
+
+// CopyProgress reports the progress of CopyTree.
+type CopyProgress struct {
+	Src, Dst   string // file being copied
+	FileBytes  int64  // bytes of this file copied so far
+	FileSize   int64  // size of this file
+	TotalBytes int64  // bytes of all files copied so far
+	Files      int64  // files completed
+}
+
+// CopyResult counts what CopyTree copied.
+type CopyResult struct {
+	Files    int64 // regular files copied
+	Dirs     int64 // directories created or merged into
+	Symlinks int64 // links recreated
+	Skipped  int64 // entries left out by the symlink or conflict policy
+	Bytes    int64 // bytes of file content copied
+}
+
+// CopyTree copies src to dst. If src is a directory its contents are
+// copied recursively into dst, which is created if needed; otherwise src
+// is copied as a file named dst. Files are written to a temporary name in
+// the destination directory and renamed into place, so a reader never
+// sees a partial file.
+//
+// On Linux file data is cloned (FICLONE) where the filesystem supports
+// reflinks, and copied in the kernel with copy_file_range otherwise.
+//
+// Failures to preserve ownership as a non-root user and extended
+// attributes on filesystems without support are ignored. The first other
+// error stops the copy; the result counts what was copied until then.
+func CopyTree(src, dst string, opts CopyOptions) (CopyResult, error) {
+	src, dst = FromSlash(src), FromSlash(dst)
+	c := &copier{opts: opts}
+	fi, err := c.stat(src)
+	if err != nil {
+		return c.res, err
+	}
+	if fi.IsDir() {
+		absSrc, err1 := filepath.Abs(src)
+		absDst, err2 := filepath.Abs(dst)
+		if err1 == nil && err2 == nil && contains(absSrc, absDst) {
+			return c.res, &fs.PathError{Op: "copy", Path: dst, Err: errors.New("destination is inside the source")}
+		}
+	}
+	err = c.copy(src, dst, "", fi, nil)
+	return c.res, err
+}
+
+// contains reports whether path is dir or below it. Both must be clean
+// absolute paths.
+func contains(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+type copier struct {
+	opts  CopyOptions
+	res   CopyResult
+	fsync bool // flush file data to disk before renaming into place
+}
+
+// stat describes path under the symlink policy. A link that cannot be
+// followed, dangling or looping on itself, is described as the link, so
+// SymlinkFollow copies it as one.
+func (c *copier) stat(path string) (fs.FileInfo, error) {
+	if c.opts.Symlinks == SymlinkFollow {
+		fi, err := os.Stat(path)
+		if err == nil {
+			return fi, nil
+		}
+		if lfi, lerr := os.Lstat(path); lerr == nil && lfi.Mode()&fs.ModeSymlink != 0 {
+			return lfi, nil
+		}
+		return nil, err
+	}
+	return os.Lstat(path)
+}
+
+// copy copies the entry src described by fi to dst. ancestors are the
+// source directories above src, used to detect loops when following links.
+func (c *copier) copy(src, dst, rel string, fi fs.FileInfo, ancestors []fs.FileInfo) error {
+	switch {
+	case fi.IsDir():
+		return c.copyDir(src, dst, rel, fi, ancestors)
+	case fi.Mode()&fs.ModeSymlink != 0:
+		if c.opts.Symlinks == SymlinkSkip {
+			c.res.Skipped++
+			return nil
+		}
+		return c.copySymlink(src, dst, fi)
+	case fi.Mode().IsRegular():
+		return c.copyFile(src, dst, fi)
+	}
+	return &fs.PathError{Op: "copy", Path: src, Err: fmt.Errorf("unsupported file type %v", fi.Mode().Type())}
+}
+
+func (c *copier) copyDir(src, dst, rel string, fi fs.FileInfo, ancestors []fs.FileInfo) error {
+	for _, a := range ancestors {
+		if os.SameFile(a, fi) {
+			return &fs.PathError{Op: "copy", Path: src, Err: errors.New("symlink loop")}
+		}
+	}
+	dfi, err := os.Lstat(dst)
+	switch {
+	case err == nil && dfi.IsDir():
+	case err == nil:
+		if dst, err = c.resolveConflict(dst); err != nil || dst == "" {
+			return err
+		}
+		fallthrough
+	case isNotExist(err):
+		if err := os.Mkdir(dst, 0o700); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	c.res.Dirs++
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	ancestors = append(ancestors, fi)
+	for _, e := range entries {
+		childRel := filepath.Join(rel, e.Name())
+		path := filepath.Join(src, e.Name())
+		cfi, err := c.stat(path)
+		if err != nil {
+			return err
+		}
+		if cfi.IsDir() {
+			if c.opts.Filter.Excluded(childRel) {
+				continue
+			}
+		} else if !c.opts.Filter.Match(childRel) {
+			continue
+		}
+		if err := c.copy(path, filepath.Join(dst, e.Name()), childRel, cfi, ancestors); err != nil {
+			return err
+		}
+	}
+	// Apply the mode last, so read-only directories can be filled.
+	return c.applyMeta(src, dst, fi)
+}
+
+// resolveConflict returns the path to copy to when dst exists, or "" to
+// skip the entry.
+func (c *copier) resolveConflict(dst string) (string, error) {
+	switch c.opts.Conflict {
+	case ConflictOverwrite:
+		if err := os.RemoveAll(dst); err != nil {
+			return "", err
+		}
+		return dst, nil
+	case ConflictSkip:
+		c.res.Skipped++
+		return "", nil
+	case ConflictRename:
+		dir, base := filepath.Split(dst)
+		ext := filepath.Ext(base)
+		stem := strings.TrimSuffix(base, ext)
+		for i := 1; ; i++ {
+			p := filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+			if _, err := os.Lstat(p); isNotExist(err) {
+				return p, nil
+			} else if err != nil {
+				return "", err
+			}
+		}
+	}
+	return "", &fs.PathError{Op: "copy", Path: dst, Err: ErrDestinationExists}
+}
+
+// checkConflict returns the path a non-directory entry is written to, or
+// "" to skip it. With ConflictOverwrite an existing file is replaced by the
+// final rename; only an existing directory is removed first.
+func (c *copier) checkConflict(dst string) (string, error) {
+	dfi, err := os.Lstat(dst)
+	if isNotExist(err) {
+		return dst, nil
+	} else if err != nil {
+		return "", err
+	}
+	if c.opts.Conflict == ConflictOverwrite && !dfi.IsDir() {
+		return dst, nil
+	}
+	return c.resolveConflict(dst)
+}
+
+func (c *copier) copySymlink(src, dst string, fi fs.FileInfo) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if dst, err = c.checkConflict(dst); err != nil || dst == "" {
+		return err
+	}
+	tmp := tempName(dst)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if c.opts.Preserve&PreserveOwner != 0 {
+		if uid, gid, ok := fileOwner(fi); ok {
+			if err := os.Lchown(tmp, uid, gid); err != nil && !errors.Is(err, fs.ErrPermission) {
+				os.Remove(tmp)
+				return err
+			}
+		}
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	c.res.Symlinks++
+	return nil
+}
+
+// tempName returns an unused-looking name next to dst for a symlink that
+// is renamed into place.
+func tempName(dst string) string {
+	dir, base := filepath.Split(dst)
+	return filepath.Join(dir, fmt.Sprintf(".%s.tmp%d", base, time.Now().UnixNano()))
+}
+
+func (c *copier) copyFile(src, dst string, fi fs.FileInfo) (err error) {
+	if dst, err = c.checkConflict(dst); err != nil || dst == "" {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(out.Name())
+		}
+	}()
+
+	var copied int64
+	progress := func(n int64) {
+		copied += n
+		c.res.Bytes += n
+		if c.opts.Progress != nil {
+			c.opts.Progress(CopyProgress{Src: src, Dst: dst, FileBytes: copied, FileSize: fi.Size(), TotalBytes: c.res.Bytes, Files: c.res.Files})
+		}
+	}
+	if err = copyFileData(out, in, progress); err != nil {
+		return err
+	}
+	if err = c.applyMeta(src, out.Name(), fi); err != nil {
+		return err
+	}
+	if c.fsync {
+		if err = out.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(out.Name(), dst); err != nil {
+		return err
+	}
+	c.res.Files++
+	if c.opts.Progress != nil {
+		c.opts.Progress(CopyProgress{Src: src, Dst: dst, FileBytes: copied, FileSize: fi.Size(), TotalBytes: c.res.Bytes, Files: c.res.Files})
+	}
+	return nil
+}
+
+// copyFileDataGeneric copies in to out with a userspace buffer.
+func copyFileDataGeneric(out, in *os.File, progress func(int64)) error {
+	buf := make([]byte, 256*1024)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			progress(int64(n))
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// applyMeta copies the metadata of the source entry fi to dst, which is
+// not a symlink.
+func (c *copier) applyMeta(src, dst string, fi fs.FileInfo) error {
+	p := c.opts.Preserve
+	if p&PreserveXattrs != 0 {
+		if err := copyXattrs(src, dst); err != nil {
+			return err
+		}
+	}
+	if p&PreserveOwner != 0 {
+		if uid, gid, ok := fileOwner(fi); ok {
+			if err := os.Lchown(dst, uid, gid); err != nil && !errors.Is(err, fs.ErrPermission) {
+				return err
+			}
+		}
+	}
+	// Set the mode after chown, which clears the setuid and setgid bits.
+	mode := fi.Mode().Perm()
+	if p&PreserveMode != 0 {
+		mode |= fi.Mode() & (fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+	}
+	if err := os.Chmod(dst, mode); err != nil {
+		return err
+	}
+	if p&PreserveTimes != 0 {
+		if err := os.Chtimes(dst, fi.ModTime(), fi.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package gofilepath
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// copyFileData copies in to out, cloning the data where the filesystem
+// supports reflinks (Btrfs, XFS, ...) and with copy_file_range otherwise,
+// falling back to a userspace copy across filesystems or on old kernels.
+func copyFileData(out, in *os.File, progress func(int64)) error {
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() > 0 && unix.IoctlFileClone(int(out.Fd()), int(in.Fd())) == nil {
+		progress(fi.Size())
+		return nil
+	}
+	const chunk = 8 << 20
+	copied := false
+	for {
+		n, err := unix.CopyFileRange(int(in.Fd()), nil, int(out.Fd()), nil, chunk, 0)
+		if err != nil {
+			if !copied && (errors.Is(err, unix.EXDEV) || errors.Is(err, unix.ENOSYS) ||
+				errors.Is(err, unix.EINVAL) || errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EPERM)) {
+				return copyFileDataGeneric(out, in, progress)
+			}
+			return &os.PathError{Op: "copy_file_range", Path: in.Name(), Err: err}
+		}
+		if n == 0 {
+			if !copied && fi.Size() > 0 {
+				// Some filesystems (procfs, sysfs) report a size but
+				// copy_file_range reads nothing from them.
+				return copyFileDataGeneric(out, in, progress)
+			}
+			return nil
+		}
+		copied = true
+		progress(int64(n))
+	}
+}
+
+// copyXattrs copies the extended attributes of src to dst. Filesystems
+// without xattr support are ignored.
+func copyXattrs(src, dst string) error {
+	size, err := unix.Llistxattr(src, nil)
+	if err != nil || size == 0 {
+		if errors.Is(err, unix.ENOTSUP) {
+			return nil
+		}
+		return wrapXattrErr("llistxattr", src, err)
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Llistxattr(src, buf); err != nil {
+		return wrapXattrErr("llistxattr", src, err)
+	}
+	for _, name := range splitNul(buf[:size]) {
+		vsize, err := unix.Lgetxattr(src, name, nil)
+		if err != nil {
+			return wrapXattrErr("lgetxattr", src, err)
+		}
+		val := make([]byte, vsize)
+		if vsize, err = unix.Lgetxattr(src, name, val); err != nil {
+			return wrapXattrErr("lgetxattr", src, err)
+		}
+		if err := unix.Lsetxattr(dst, name, val[:vsize], 0); err != nil {
+			// Unprivileged users cannot set trusted.* and security.* names.
+			if errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EPERM) {
+				continue
+			}
+			return wrapXattrErr("lsetxattr", dst, err)
+		}
+	}
+	return nil
+}
+
+func wrapXattrErr(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
+
+// splitNul splits a list of NUL-terminated strings.
+func splitNul(b []byte) []string {
+	var names []string
+	start := 0
+	for i, c := range b {
+		if c == 0 {
+			if i > start {
+				names = append(names, string(b[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return names
+}
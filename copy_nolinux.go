@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package gofilepath
+
+import "os"
+
+func copyFileData(out, in *os.File, progress func(int64)) error {
+	return copyFileDataGeneric(out, in, progress)
+}
+
+// copyXattrs is a no-op: extended attributes are only copied on Linux.
+func copyXattrs(src, dst string) error {
+	return nil
+}
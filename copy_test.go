@@ -0,0 +1,114 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyTree(t *testing.T) {
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	writeTree(t, src, map[string]string{
+		"a.txt":       "alpha",
+		"sub/b.txt":   "beta",
+		"sub/c.log":   "log",
+		"cache/d.txt": "cached",
+	})
+	os.Chmod(filepath.Join(src, "a.txt"), 0o600)
+	mtime := time.Unix(1600000000, 0)
+	os.Chtimes(filepath.Join(src, "sub", "b.txt"), mtime, mtime)
+	os.Symlink("a.txt", filepath.Join(src, "link"))
+
+	var progress int
+	res, err := CopyTree(src, dst, CopyOptions{
+		Preserve: PreserveAll,
+		Filter:   Filter{Include: []string{"*.txt", "link"}, Exclude: []string{"cache"}},
+		Progress: func(CopyProgress) { progress++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Files != 2 || res.Symlinks != 1 || res.Bytes != 9 {
+		t.Errorf("result = %+v", res)
+	}
+	if progress == 0 {
+		t.Error("Progress was not called")
+	}
+	if got := readFile(t, filepath.Join(dst, "sub", "b.txt")); got != "beta" {
+		t.Errorf("sub/b.txt = %q", got)
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "sub", "b.txt")); err != nil || !fi.ModTime().Equal(mtime) {
+		t.Errorf("mtime not preserved: %v %v", fi, err)
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode not preserved: %v %v", fi, err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "a.txt" {
+		t.Errorf("link = %q, %v", target, err)
+	}
+	for _, name := range []string{"sub/c.log", "cache"} {
+		if ok, _ := LExists(filepath.Join(dst, name)); ok {
+			t.Errorf("%s was copied", name)
+		}
+	}
+}
+
+func TestCopyTreeConflict(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeTree(t, src, map[string]string{"f.txt": "new"})
+	writeTree(t, dst, map[string]string{"f.txt": "old"})
+	f := filepath.Join(dst, "f.txt")
+
+	if _, err := CopyTree(src, dst, CopyOptions{}); !errors.Is(err, ErrDestinationExists) {
+		t.Errorf("ConflictError: err = %v", err)
+	}
+	if res, err := CopyTree(src, dst, CopyOptions{Conflict: ConflictSkip}); err != nil || res.Skipped != 1 || readFile(t, f) != "old" {
+		t.Errorf("ConflictSkip: %+v, %v", res, err)
+	}
+	if _, err := CopyTree(src, dst, CopyOptions{Conflict: ConflictRename}); err != nil || readFile(t, filepath.Join(dst, "f (1).txt")) != "new" {
+		t.Errorf("ConflictRename: %v", err)
+	}
+	if _, err := CopyTree(src, dst, CopyOptions{Conflict: ConflictOverwrite}); err != nil || readFile(t, f) != "new" {
+		t.Errorf("ConflictOverwrite: %v", err)
+	}
+}
+
+func TestCopyTreeFollowDangling(t *testing.T) {
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "out")
+	writeTree(t, src, map[string]string{"f": "x"})
+	os.Symlink("f", filepath.Join(src, "good"))
+	os.Symlink("missing", filepath.Join(src, "dangling"))
+	res, err := CopyTree(src, dst, CopyOptions{Symlinks: SymlinkFollow})
+	if err != nil || res.Files != 2 || res.Symlinks != 1 {
+		t.Fatalf("CopyTree = %+v, %v", res, err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "dangling")); err != nil || target != "missing" {
+		t.Errorf("dangling link copied as %q, %v", target, err)
+	}
+	if fi, err := os.Lstat(filepath.Join(dst, "good")); err != nil || !fi.Mode().IsRegular() {
+		t.Errorf("followed link: %v, %v", fi, err)
+	}
+}
+
+func TestCopyTreeErrors(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"sub/f": "x"})
+	if _, err := CopyTree(src, filepath.Join(src, "sub", "copy"), CopyOptions{}); err == nil {
+		t.Error("copy into the source succeeded")
+	}
+
+	os.Symlink("..", filepath.Join(src, "sub", "up"))
+	_, err := CopyTree(src, filepath.Join(t.TempDir(), "out"), CopyOptions{Symlinks: SymlinkFollow})
+	if err == nil {
+		t.Error("following a symlink loop succeeded")
+	}
+	res, err := CopyTree(src, filepath.Join(t.TempDir(), "out"), CopyOptions{Symlinks: SymlinkSkip})
+	if err != nil || res.Skipped != 1 || res.Files != 1 {
+		t.Errorf("SymlinkSkip: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,163 @@
+package gofilepath
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DiskUsageInfo is the space and inode usage of a filesystem.
+// Inode counts are zero where the platform does not report them (Windows).
+type DiskUsageInfo struct {
+	TotalBytes  uint64 // size of the filesystem
+	FreeBytes   uint64 // free bytes, including those reserved for root
+	AvailBytes  uint64 // free bytes available to unprivileged users
+	TotalInodes uint64
+	FreeInodes  uint64
+}
+
+// UsedBytes returns TotalBytes - FreeBytes.
+func (d DiskUsageInfo) UsedBytes() uint64 {
+	return d.TotalBytes - d.FreeBytes
+}
+
+// DiskUsage returns the usage of the filesystem that contains path
+// (statfs on Unix, GetDiskFreeSpaceEx on Windows).
+func DiskUsage(path string) (DiskUsageInfo, error) {
+	return diskUsage(FromSlash(path))
+}
+
+// fileID identifies a file on a device, used to count hard links once.
+type fileID struct {
+	dev, ino uint64
+}
+
+// DirSizeOptions controls DirSize.
+type DirSizeOptions struct {
+	// MaxDepth limits the entries counted, like the maxdeep argument of
+	// FindFilesMatchPathFromRoot: 0 counts only the entries directly in
+	// root. Negative means unlimited.
+	MaxDepth int
+
+	// Parallel is the maximum number of directories read at the same
+	// time. Zero or one reads sequentially.
+	Parallel int
+
+	// Progress, if not nil, is called with the running totals after each
+	// directory has been read. Calls are never concurrent.
+	Progress func(DirSizeResult)
+}
+
+// DirSizeResult holds the totals computed by DirSize.
+type DirSizeResult struct {
+	Files          int64 // non-directory entries counted
+	Dirs           int64 // directories below root
+	ApparentBytes  int64 // sum of file sizes
+	AllocatedBytes int64 // sum of the disk space used by the files
+	HardLinks      int64 // extra names of hard-linked files, not counted again
+	Errors         int64 // entries or directories that could not be read
+}
+
+// DirSize sums the sizes of the files below root. Symlinks are counted as
+// links and never followed. Files with several hard links are counted once
+// where the platform exposes inode numbers. Directories themselves do not
+// add to the byte totals.
+//
+// Unreadable entries are skipped and counted in Errors; only a failure to
+// stat root is returned as an error.
+func DirSize(root string, opts DirSizeOptions) (DirSizeResult, error) {
+	root = FromSlash(root)
+	fi, err := os.Lstat(root)
+	if err != nil {
+		return DirSizeResult{}, err
+	}
+
+	ds := &dirSizer{opts: opts, seen: map[fileID]bool{}}
+	if !fi.IsDir() {
+		ds.addFile(fi)
+		return ds.res, nil
+	}
+	if opts.Parallel > 1 {
+		ds.sem = make(chan struct{}, opts.Parallel-1)
+	}
+	ds.walk(root, 0)
+	ds.wg.Wait()
+	return ds.res, nil
+}
+
+type dirSizer struct {
+	opts DirSizeOptions
+	sem  chan struct{} // extra goroutines allowed, nil when sequential
+	wg   sync.WaitGroup
+
+	mu   sync.Mutex
+	res  DirSizeResult
+	seen map[fileID]bool
+}
+
+// addFile counts a non-directory entry. The caller must hold mu when
+// running concurrently.
+func (ds *dirSizer) addFile(fi fs.FileInfo) {
+	id, nlink, allocated, ok := fileIdentity(fi)
+	if ok && nlink > 1 && fi.Mode().IsRegular() {
+		if ds.seen[id] {
+			ds.res.HardLinks++
+			return
+		}
+		ds.seen[id] = true
+	}
+	ds.res.Files++
+	ds.res.ApparentBytes += fi.Size()
+	ds.res.AllocatedBytes += allocated
+}
+
+// walk counts the entries of dir, which are at the given depth.
+func (ds *dirSizer) walk(dir string, depth int) {
+	if ds.opts.MaxDepth >= 0 && depth > ds.opts.MaxDepth {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	errs := 0
+	if err != nil {
+		errs++
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil {
+			errs++
+			continue
+		}
+		infos = append(infos, fi)
+	}
+	subdirs := []string{}
+
+	ds.mu.Lock()
+	ds.res.Errors += int64(errs)
+	for _, fi := range infos {
+		if fi.IsDir() {
+			ds.res.Dirs++
+			subdirs = append(subdirs, filepath.Join(dir, fi.Name()))
+			continue
+		}
+		ds.addFile(fi)
+	}
+	if ds.opts.Progress != nil {
+		ds.opts.Progress(ds.res)
+	}
+	ds.mu.Unlock()
+
+	for _, sub := range subdirs {
+		select {
+		case ds.sem <- struct{}{}: // never ready when ds.sem is nil
+			ds.wg.Add(1)
+			go func(sub string) {
+				defer func() { <-ds.sem; ds.wg.Done() }()
+				ds.walk(sub, depth+1)
+			}(sub)
+		default:
+			ds.walk(sub, depth+1)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !windows
+// +build !linux,!darwin,!freebsd,!windows
+
+package gofilepath
+
+import "errors"
+
+func diskUsage(path string) (DiskUsageInfo, error) {
+	return DiskUsageInfo{}, errors.ErrUnsupported
+}
@@ -0,0 +1,53 @@
+//go:build unix
+
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	du, err := DiskUsage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if du.TotalBytes == 0 || du.AvailBytes > du.TotalBytes || du.UsedBytes() > du.TotalBytes {
+		t.Errorf("DiskUsage = %+v", du)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "a"), make([]byte, 100), 0o644)
+	os.Link(filepath.Join(root, "a"), filepath.Join(root, "a-link"))
+	os.MkdirAll(filepath.Join(root, "d1", "d2"), 0o755)
+	os.WriteFile(filepath.Join(root, "d1", "b"), make([]byte, 10), 0o644)
+	os.WriteFile(filepath.Join(root, "d1", "d2", "c"), make([]byte, 1), 0o644)
+
+	tests := []struct {
+		opts         DirSizeOptions
+		files, bytes int64
+		links        int64
+	}{
+		{DirSizeOptions{MaxDepth: -1}, 3, 111, 1},
+		{DirSizeOptions{MaxDepth: -1, Parallel: 4}, 3, 111, 1},
+		{DirSizeOptions{MaxDepth: 0}, 1, 100, 1},
+		{DirSizeOptions{MaxDepth: 1}, 2, 110, 1},
+	}
+	for _, tt := range tests {
+		calls := 0
+		tt.opts.Progress = func(DirSizeResult) { calls++ }
+		res, err := DirSize(root, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Files != tt.files || res.ApparentBytes != tt.bytes || res.HardLinks != tt.links {
+			t.Errorf("DirSize(%+v) = %+v, want files %d bytes %d links %d", tt.opts, res, tt.files, tt.bytes, tt.links)
+		}
+		if calls == 0 {
+			t.Errorf("DirSize(%+v): Progress never called", tt.opts)
+		}
+	}
+}
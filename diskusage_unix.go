@@ -0,0 +1,25 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package gofilepath
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/unix"
+)
+
+func diskUsage(path string) (DiskUsageInfo, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return DiskUsageInfo{}, &fs.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	bsize := uint64(st.Bsize)
+	return DiskUsageInfo{
+		TotalBytes:  uint64(st.Blocks) * bsize,
+		FreeBytes:   uint64(st.Bfree) * bsize,
+		AvailBytes:  uint64(st.Bavail) * bsize,
+		TotalInodes: uint64(st.Files),
+		FreeInodes:  uint64(st.Ffree),
+	}, nil
+}
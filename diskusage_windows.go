@@ -0,0 +1,19 @@
+package gofilepath
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/windows"
+)
+
+func diskUsage(path string) (DiskUsageInfo, error) {
+	var du DiskUsageInfo
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return du, err
+	}
+	if err = windows.GetDiskFreeSpaceEx(p, &du.AvailBytes, &du.TotalBytes, &du.FreeBytes); err != nil {
+		return du, &fs.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+	return du, nil
+}
@@ -0,0 +1,91 @@
+package gofilepath
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// The functions in this file answer "is it there?" with a tri-state result:
+// (true, nil) and (false, nil) are definite answers, while a non-nil error
+// means the answer is unknown (permission denied, I/O error, ...).
+//
+// Functions whose name starts with L use os.Lstat and look at a symlink
+// itself; the others use os.Stat and follow symlinks.
+
+// isNotExist reports whether err means that the path does not exist.
+// ENOTDIR (a path component is not a directory) is treated as not existing.
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR)
+}
+
+func statFollow(path string, follow bool) (fs.FileInfo, error) {
+	if follow {
+		return os.Stat(path)
+	}
+	return os.Lstat(path)
+}
+
+// statKind returns the kind of path, KindNone with a nil error if it does
+// not exist, or the stat error.
+func statKind(path string, follow bool) (PathKind, error) {
+	fi, err := statFollow(path, follow)
+	if err != nil {
+		if isNotExist(err) {
+			return KindNone, nil
+		}
+		return KindNone, err
+	}
+	return KindOf(fi.Mode()), nil
+}
+
+// Exists reports whether path exists, following symlinks. A dangling
+// symlink does not exist.
+func Exists(path string) (bool, error) {
+	k, err := statKind(path, true)
+	return k != KindNone, err
+}
+
+// LExists reports whether path exists without following symlinks.
+// A dangling symlink exists.
+func LExists(path string) (bool, error) {
+	k, err := statKind(path, false)
+	return k != KindNone, err
+}
+
+// IsDir reports whether path is a directory or a symlink to one.
+func IsDir(path string) (bool, error) {
+	k, err := statKind(path, true)
+	return k == KindDir, err
+}
+
+// LIsDir reports whether path is a directory and not a symlink.
+func LIsDir(path string) (bool, error) {
+	k, err := statKind(path, false)
+	return k == KindDir, err
+}
+
+// IsRegular reports whether path is a regular file or a symlink to one.
+func IsRegular(path string) (bool, error) {
+	k, err := statKind(path, true)
+	return k == KindFile, err
+}
+
+// LIsRegular reports whether path is a regular file and not a symlink.
+func LIsRegular(path string) (bool, error) {
+	k, err := statKind(path, false)
+	return k == KindFile, err
+}
+
+// IsSymlink reports whether path is a symlink. It never follows the link.
+func IsSymlink(path string) (bool, error) {
+	k, err := statKind(path, false)
+	return k == KindSymlink, err
+}
+
+// IsSocket reports whether path is a Unix domain socket or a symlink to one.
+func IsSocket(path string) (bool, error) {
+	k, err := statKind(path, true)
+	return k == KindSocket, err
+}
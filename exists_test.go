@@ -0,0 +1,39 @@
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExistsTriState(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, []byte("x"), 0o644)
+
+	tests := []struct {
+		name string
+		fn   func(string) (bool, error)
+		path string
+		want bool
+	}{
+		{"Exists file", Exists, file, true},
+		{"Exists missing", Exists, filepath.Join(dir, "missing"), false},
+		{"Exists under file (ENOTDIR)", Exists, filepath.Join(file, "child"), false},
+		{"IsDir dir", IsDir, dir, true},
+		{"IsDir file", IsDir, file, false},
+		{"IsRegular file", IsRegular, file, true},
+		{"LIsDir under file", LIsDir, filepath.Join(file, "child"), false},
+	}
+	for _, tt := range tests {
+		got, err := tt.fn(tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("%s(%q) = %v, %v, want %v, nil", tt.name, tt.path, got, err, tt.want)
+		}
+	}
+
+	empty, err := DirIsEmpty(dir)
+	if err != nil || empty {
+		t.Errorf("DirIsEmpty(%q) = %v, %v, want false, nil", dir, empty, err)
+	}
+}
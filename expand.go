@@ -0,0 +1,235 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+)
+
+// ErrUndefinedVar is returned by Expand in strict mode when a variable
+// without a default is not set.
+var ErrUndefinedVar = errors.New("undefined variable")
+
+// ExpandSyntax selects the constructs Expand recognizes.
+type ExpandSyntax int
+
+const (
+	ExpandTilde   ExpandSyntax = 1 << iota // ~ and ~user at the start of the path
+	ExpandPOSIX                            // $VAR, ${VAR}, ${VAR:-default}, ${VAR-default}
+	ExpandWindows                          // %VAR%
+
+	ExpandAll = ExpandTilde | ExpandPOSIX | ExpandWindows
+)
+
+// ExpandOptions controls Expand and Contract.
+type ExpandOptions struct {
+	// Syntax selects what is expanded. Zero means ExpandAll.
+	Syntax ExpandSyntax
+
+	// Strict makes undefined variables without a default an error instead
+	// of expanding to "".
+	Strict bool
+
+	// LookupEnv returns the value of a variable. Nil means os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+
+	// HomeDir returns the home directory of username, or of the current
+	// user if username is "". Nil means os.UserHomeDir and os/user.Lookup.
+	HomeDir func(username string) (string, error)
+}
+
+func (o ExpandOptions) syntax() ExpandSyntax {
+	if o.Syntax == 0 {
+		return ExpandAll
+	}
+	return o.Syntax
+}
+
+func (o ExpandOptions) lookupEnv(key string) (string, bool) {
+	if o.LookupEnv != nil {
+		return o.LookupEnv(key)
+	}
+	return os.LookupEnv(key)
+}
+
+func (o ExpandOptions) homeDir(username string) (string, error) {
+	if o.HomeDir != nil {
+		return o.HomeDir(username)
+	}
+	if username == "" {
+		return os.UserHomeDir()
+	}
+	u, err := user.Lookup(username)
+	if err != nil {
+		return "", err
+	}
+	return u.HomeDir, nil
+}
+
+func isSeparatorByte(c byte) bool {
+	return c == '/' || c == '\\'
+}
+
+func isVarNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (!first && c >= '0' && c <= '9')
+}
+
+// Expand expands a leading "~" or "~user" and environment variables in p.
+// Both POSIX ($VAR, ${VAR}, ${VAR:-default}, ${VAR-default}) and Windows
+// (%VAR%) syntax are recognized unless opts.Syntax restricts them. Defaults
+// are expanded too, so "${XDG_DATA_HOME:-~/.local/share}" works. Text that
+// does not form a valid reference, like a lone "$" or "100%", is kept.
+func Expand(p string, opts ExpandOptions) (string, error) {
+	syntax := opts.syntax()
+	var sb strings.Builder
+	i := 0
+	if syntax&ExpandTilde != 0 && strings.HasPrefix(p, "~") {
+		end := 1
+		for end < len(p) && !isSeparatorByte(p[end]) {
+			end++
+		}
+		home, err := opts.homeDir(p[1:end])
+		if err != nil {
+			return "", fmt.Errorf("expand %q: %w", p[:end], err)
+		}
+		sb.WriteString(home)
+		i = end
+	}
+
+	for i < len(p) {
+		c := p[i]
+		switch {
+		case c == '$' && syntax&ExpandPOSIX != 0:
+			val, n, err := expandDollar(p[i:], opts)
+			if err != nil {
+				return "", err
+			}
+			if n == 0 {
+				break
+			}
+			sb.WriteString(val)
+			i += n
+			continue
+		case c == '%' && syntax&ExpandWindows != 0:
+			end := strings.IndexByte(p[i+1:], '%')
+			if end <= 0 {
+				break
+			}
+			name := p[i+1 : i+1+end]
+			if strings.ContainsAny(name, ` /\`) {
+				break
+			}
+			val, ok := opts.lookupEnv(name)
+			if !ok {
+				if opts.Strict {
+					return "", fmt.Errorf("expand %%%s%%: %w", name, ErrUndefinedVar)
+				}
+				break // cmd.exe keeps unknown %VAR% as is
+			}
+			sb.WriteString(val)
+			i += end + 2
+			continue
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	return sb.String(), nil
+}
+
+// expandDollar expands the POSIX reference at the start of s and returns
+// the value and the number of bytes consumed, or 0 if s does not start
+// with a valid reference.
+func expandDollar(s string, opts ExpandOptions) (string, int, error) {
+	if len(s) < 2 {
+		return "", 0, nil
+	}
+	if s[1] != '{' {
+		n := 1
+		for n < len(s) && isVarNameByte(s[n], n == 1) {
+			n++
+		}
+		if n == 1 {
+			return "", 0, nil
+		}
+		val, err := lookupVar(s[1:n], opts)
+		return val, n, err
+	}
+
+	// ${...}: find the matching brace, allowing nesting in the default.
+	depth, end := 0, -1
+	for j := 1; j < len(s) && end < 0; j++ {
+		switch s[j] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				end = j
+			}
+		}
+	}
+	if end < 0 {
+		return "", 0, nil
+	}
+	body := s[2:end]
+	n := 0
+	for n < len(body) && isVarNameByte(body[n], n == 0) {
+		n++
+	}
+	if n == 0 {
+		return "", 0, nil
+	}
+	name, rest := body[:n], body[n:]
+	val, ok := opts.lookupEnv(name)
+	switch {
+	case rest == "":
+		if !ok && opts.Strict {
+			return "", 0, fmt.Errorf("expand ${%s}: %w", name, ErrUndefinedVar)
+		}
+		return val, end + 1, nil
+	case strings.HasPrefix(rest, ":-"), strings.HasPrefix(rest, "-"):
+		unsetOnly := rest[0] == '-'
+		def := strings.TrimPrefix(strings.TrimPrefix(rest, ":"), "-")
+		if ok && (unsetOnly || val != "") {
+			return val, end + 1, nil
+		}
+		val, err := Expand(def, opts)
+		return val, end + 1, err
+	}
+	return "", 0, nil
+}
+
+func lookupVar(name string, opts ExpandOptions) (string, error) {
+	val, ok := opts.lookupEnv(name)
+	if !ok && opts.Strict {
+		return "", fmt.Errorf("expand $%s: %w", name, ErrUndefinedVar)
+	}
+	return val, nil
+}
+
+// Contract replaces the home directory of the current user at the start of
+// p with "~", for display. It is the inverse of the tilde part of Expand.
+//
+//	Contract("/home/me/data", ExpandOptions{}) → "~/data"
+func Contract(p string, opts ExpandOptions) string {
+	home, err := opts.homeDir("")
+	if err != nil || home == "" {
+		return p
+	}
+	home = strings.TrimRight(home, `/\`)
+	if home == "" || len(p) < len(home) {
+		return p
+	}
+	// Windows homes match case-insensitively and with either separator.
+	if prefix := p[:len(home)]; prefix != home && (driveLetter(home) == "" || !EqualPaths(prefix, home, FlavorWindows)) {
+		return p
+	}
+	if len(p) == len(home) {
+		return "~"
+	}
+	if !isSeparatorByte(p[len(home)]) {
+		return p
+	}
+	return "~" + p[len(home):]
+}
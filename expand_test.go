@@ -0,0 +1,84 @@
+package gofilepath
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{"HOME": "/home/me", "APPDATA": `C:\Users\me\AppData`, "EMPTY": ""}
+	opts := ExpandOptions{
+		LookupEnv: func(k string) (string, bool) { v, ok := env[k]; return v, ok },
+		HomeDir: func(u string) (string, error) {
+			switch u {
+			case "":
+				return "/home/me", nil
+			case "bob":
+				return "/home/bob", nil
+			}
+			return "", errors.New("unknown user " + u)
+		},
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"~", "/home/me"},
+		{"~/data", "/home/me/data"},
+		{"~bob/x", "/home/bob/x"},
+		{"$HOME/cache", "/home/me/cache"},
+		{"${HOME}/cache", "/home/me/cache"},
+		{"${XDG_DATA_HOME:-~/.local/share}/app", "/home/me/.local/share/app"},
+		{"${EMPTY:-def}", "def"},
+		{"${EMPTY-def}", ""},
+		{`%APPDATA%\app`, `C:\Users\me\AppData\app`},
+		{"100%", "100%"},
+		{"%UNSET%", "%UNSET%"},
+		{"cost$", "cost$"},
+		{"a/$UNSET/b", "a//b"},
+		{"x~y", "x~y"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.in, opts)
+		if err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	strict := opts
+	strict.Strict = true
+	for _, in := range []string{"$UNSET", "${UNSET}", "%UNSET%"} {
+		if _, err := Expand(in, strict); !errors.Is(err, ErrUndefinedVar) {
+			t.Errorf("Expand(%q) strict error = %v, want ErrUndefinedVar", in, err)
+		}
+	}
+	if _, err := Expand("${UNSET:-ok}", strict); err != nil {
+		t.Errorf("Expand with default in strict mode: %v", err)
+	}
+	if _, err := Expand("~nobody/x", opts); err == nil {
+		t.Errorf("Expand(~nobody) should fail")
+	}
+	if got, err := Expand("$HOME/%APPDATA%", ExpandOptions{Syntax: ExpandPOSIX, LookupEnv: opts.LookupEnv}); err != nil || got != "/home/me/%APPDATA%" {
+		t.Errorf("Expand POSIX only = %q, %v", got, err)
+	}
+}
+
+func TestContract(t *testing.T) {
+	posix := ExpandOptions{HomeDir: func(string) (string, error) { return "/home/me", nil }}
+	windows := ExpandOptions{HomeDir: func(string) (string, error) { return `C:\Users\me`, nil }}
+	tests := []struct {
+		in   string
+		opts ExpandOptions
+		want string
+	}{
+		{"/home/me/data", posix, "~/data"},
+		{"/home/me", posix, "~"},
+		{"/home/meow", posix, "/home/meow"},
+		{"/srv/x", posix, "/srv/x"},
+		{`c:/users/ME/docs`, windows, "~/docs"},
+	}
+	for _, tt := range tests {
+		if got := Contract(tt.in, tt.opts); got != tt.want {
+			t.Errorf("Contract(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
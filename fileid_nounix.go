@@ -0,0 +1,16 @@
+//go:build !unix
+
+package gofilepath
+
+import "io/fs"
+
+// fileIdentity has no inode to report outside Unix: every name is counted
+// as its own file and the allocated size is the apparent size.
+func fileIdentity(fi fs.FileInfo) (id fileID, nlink uint64, allocated int64, ok bool) {
+	return fileID{}, 1, fi.Size(), false
+}
+
+// fileOwner reports no owner outside Unix.
+func fileOwner(fi fs.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package gofilepath
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileIdentity returns the device and inode of fi, its link count and the
+// bytes allocated for it on disk.
+func fileIdentity(fi fs.FileInfo) (id fileID, nlink uint64, allocated int64, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, 1, fi.Size(), false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, uint64(st.Nlink), int64(st.Blocks) * 512, true
+}
+
+// fileOwner returns the user and group IDs of fi.
+func fileOwner(fi fs.FileInfo) (uid, gid int, ok bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
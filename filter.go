@@ -0,0 +1,69 @@
+package gofilepath
+
+import (
+	"path/filepath"
+
+	"github.com/sonnt85/gosutils/sregexp"
+)
+
+// MatchName reports whether the base name of relpath matches the glob
+// pattern, as FindFilesMatchName does.
+func MatchName(pattern, relpath string) bool {
+	match, err := filepath.Match(pattern, filepath.Base(relpath))
+	return err == nil && match
+}
+
+// MatchRegexpName reports whether the base name of relpath matches the
+// regular expression pattern, as FindFilesMatchRegexpName does.
+func MatchRegexpName(pattern, relpath string) bool {
+	return sregexp.New(pattern).MatchString(filepath.Base(relpath))
+}
+
+// MatchRegexpPath reports whether relpath matches the regular expression
+// pattern, as FindFilesMatchRegexpPathFromRoot does.
+func MatchRegexpPath(pattern, relpath string) bool {
+	return sregexp.New(pattern).MatchString(relpath)
+}
+
+// Filter selects entries of a tree by their path relative to the root,
+// with the matchers used by the finders.
+// The zero Filter matches everything.
+type Filter struct {
+	// Include patterns; an entry must match one of them. Empty matches all.
+	Include []string
+
+	// Exclude patterns; an entry matching one of them is skipped, and an
+	// excluded directory is not descended into.
+	Exclude []string
+
+	// MatchFunc compares a pattern with a relative path, e.g. MatchName
+	// (the default), MatchRegexpName or MatchRegexpPath.
+	MatchFunc func(pattern, relpath string) bool
+}
+
+func (f Filter) matchAny(patterns []string, relpath string) bool {
+	match := f.MatchFunc
+	if match == nil {
+		match = MatchName
+	}
+	for _, p := range patterns {
+		if match(p, relpath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded reports whether relpath matches an Exclude pattern.
+func (f Filter) Excluded(relpath string) bool {
+	return f.matchAny(f.Exclude, relpath)
+}
+
+// Match reports whether relpath is selected: not excluded and, if Include
+// is set, matching one of its patterns.
+func (f Filter) Match(relpath string) bool {
+	if f.Excluded(relpath) {
+		return false
+	}
+	return len(f.Include) == 0 || f.matchAny(f.Include, relpath)
+}
@@ -0,0 +1,119 @@
+package gofilepath
+
+import (
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Flavor selects the path conventions of an operating system, so paths
+// and PATH-like lists of one system can be handled on another.
+type Flavor int
+
+const (
+	FlavorHost    Flavor = iota // conventions of the running OS
+	FlavorPOSIX                 // Linux, BSD and other Unix systems
+	FlavorWindows               // Windows
+	FlavorDarwin                // macOS: POSIX syntax, case-insensitive by default
+)
+
+// Resolve returns f, with FlavorHost replaced by the flavor of the running OS.
+func (f Flavor) Resolve() Flavor {
+	if f != FlavorHost {
+		return f
+	}
+	switch runtime.GOOS {
+	case "windows":
+		return FlavorWindows
+	case "darwin", "ios":
+		return FlavorDarwin
+	}
+	return FlavorPOSIX
+}
+
+// Separator returns the preferred path separator of f.
+func (f Flavor) Separator() byte {
+	if f.Resolve() == FlavorWindows {
+		return '\\'
+	}
+	return '/'
+}
+
+// ListSeparator returns the separator used in PATH-like lists.
+func (f Flavor) ListSeparator() byte {
+	if f.Resolve() == FlavorWindows {
+		return ';'
+	}
+	return ':'
+}
+
+func (f Flavor) String() string {
+	switch f {
+	case FlavorHost:
+		return "host"
+	case FlavorPOSIX:
+		return "posix"
+	case FlavorWindows:
+		return "windows"
+	case FlavorDarwin:
+		return "darwin"
+	}
+	return "unknown"
+}
+
+// SplitListFlavor splits a PATH-like list using the list separator of f.
+// For FlavorWindows, separators inside double quotes are not split on and
+// the quotes are removed, like filepath.SplitList does on Windows.
+// An empty list returns an empty slice.
+func SplitListFlavor(list string, f Flavor) []string {
+	if list == "" {
+		return []string{}
+	}
+	sep := f.ListSeparator()
+	if f.Resolve() != FlavorWindows {
+		return strings.Split(list, string(sep))
+	}
+
+	entries := []string{}
+	var cur strings.Builder
+	quoted := false
+	for i := 0; i < len(list); i++ {
+		switch c := list[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			entries = append(entries, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(entries, cur.String())
+}
+
+// JoinFlavor joins path elements with the separator of f and cleans the
+// result. For FlavorWindows both '/' and '\' are accepted in elem, and
+// drive and UNC volumes are kept.
+//
+//	JoinFlavor(FlavorWindows, `C:\Users`, "me/AppData") → `C:\Users\me\AppData`
+func JoinFlavor(f Flavor, elem ...string) string {
+	if f.Resolve() != FlavorWindows {
+		return path.Join(elem...)
+	}
+	for i, e := range elem {
+		if e == "" {
+			continue
+		}
+		vol, rest := SplitVolumeFlavor(e, f)
+		parts := []string{rest}
+		for _, e := range elem[i+1:] {
+			parts = append(parts, NormalizeSeparators(e))
+		}
+		rest = path.Join(parts...)
+		if strings.HasPrefix(vol, "//") && rest != "" && rest[0] != '/' {
+			rest = "/" + rest
+		}
+		return strings.ReplaceAll(vol+rest, "/", `\`)
+	}
+	return ""
+}
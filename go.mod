@@ -1,8 +1,10 @@
 module github.com/sonnt85/gofilepath
 
-go 1.21
+go 1.25.0
 
 require (
+	github.com/klauspost/compress v1.17.11
 	github.com/sonnt85/gosutils v0.0.0-20251021114853-09b4d7cee7a2
 	golang.org/x/sys v0.43.0
+	golang.org/x/text v0.22.0
 )
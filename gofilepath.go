@@ -361,12 +361,17 @@ func FindFilesMatchPathFromRoot(root, pattern string, maxdeep int, matchfile, ma
 	// 	root += string(os.PathSeparator)
 	// }
 	rootPath := filepath.FromSlash(root)
+	rootIsFile := false
 	if finfo, err := os.Stat(root); err == nil {
 		if !finfo.IsDir() { //is file
 			if matchFunc(pattern, root) {
 				matches = []string{root}
 			}
-			return
+			if len(walkdirs) == 0 {
+				return
+			}
+			// A custom walker may still look inside, e.g. ArchiveWalker.
+			rootIsFile = true
 		}
 	}
 	pattern = filepath.FromSlash(pattern)
@@ -376,7 +381,7 @@ func FindFilesMatchPathFromRoot(root, pattern string, maxdeep int, matchfile, ma
 	}
 	var relpath string
 	var deep int
-	if nil != walkdir(rootPath, func(path string, d fs.DirEntry, err error) error {
+	visit := func(path string, d fs.DirEntry, err error) error {
 		if err != nil { //signaling that Walk will not walk into this directory.
 			// return err
 			return nil
@@ -417,6 +422,20 @@ func FindFilesMatchPathFromRoot(root, pattern string, maxdeep int, matchfile, ma
 			}
 		}
 		return nil
+	}
+	if nil != walkdir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if path == rootPath && rootIsFile {
+			return nil // matched above
+		}
+		if err := visit(path, d, err); err != nil || !isArchiveEntry(d) || maxdeep < 0 {
+			return err
+		}
+		// Do not let ArchiveWalker open an archive whose members are all
+		// deeper than maxdeep.
+		if rel, err := filepath.Rel(rootPath, path); err == nil && strings.Count(rel, string(os.PathSeparator)) >= maxdeep {
+			return SkipArchive
+		}
+		return nil
 	}) {
 		return nil
 	}
@@ -438,10 +457,7 @@ func FindFilesMatchPathFromRoot(root, pattern string, maxdeep int, matchfile, ma
 // Returns:
 //   - matches: A slice of strings containing the paths of the matching files and directories.
 func FindFilesMatchRegexpPathFromRoot(root, pattern string, maxdeep int, matchfile, matchdir bool, walkdirs ...WarkdirFunc) (matches []string) {
-	matchFunc := func(pattern, relpath string) bool {
-		return sregexp.New(pattern).MatchString(relpath)
-	}
-	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, matchFunc, walkdirs...)
+	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, MatchRegexpPath, walkdirs...)
 }
 
 // FindFilesMatchRegexpName finds files and directories that match a regular expression pattern
@@ -459,10 +475,7 @@ func FindFilesMatchRegexpPathFromRoot(root, pattern string, maxdeep int, matchfi
 // Returns:
 //   - matches: A slice of strings containing the paths of the matching files and directories.
 func FindFilesMatchRegexpName(root, pattern string, maxdeep int, matchfile, matchdir bool, walkdirs ...WarkdirFunc) (matches []string) {
-	matchFunc := func(pattern, relpath string) bool {
-		return sregexp.New(pattern).MatchString(filepath.Base(relpath))
-	}
-	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, matchFunc, walkdirs...)
+	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, MatchRegexpName, walkdirs...)
 }
 
 // FindFilesMatchName finds files and directories whose names match the specified pattern
@@ -480,13 +493,7 @@ func FindFilesMatchRegexpName(root, pattern string, maxdeep int, matchfile, matc
 // Returns:
 //   - matches: A slice of strings containing the paths of the matching files and directories.
 func FindFilesMatchName(root, pattern string, maxdeep int, matchfile, matchdir bool, walkdirs ...WarkdirFunc) (matches []string) {
-	matchFunc := func(pattern, relpath string) bool {
-		if match, err := filepath.Match(pattern, filepath.Base(relpath)); err == nil && match {
-			return true
-		}
-		return false
-	}
-	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, matchFunc, walkdirs...)
+	return FindFilesMatchPathFromRoot(root, pattern, maxdeep, matchfile, matchdir, MatchName, walkdirs...)
 }
 
 func GetDrives() ([]string, error) {
@@ -518,106 +525,70 @@ func TempFileCreateWithContent(data []byte, filename ...string) (fpath string) {
 	return
 }
 
+// Cat returns the contents of files joined by "\n". It stops at the first
+// file that cannot be read. Use CatTo to stream large files.
 func Cat(files ...string) (contents string, err error) {
+	var sb strings.Builder
 	var tmpbytes []byte
 	for _, fname := range files {
 		tmpbytes, err = os.ReadFile(fname)
 		if err != nil {
-			return
+			return sb.String(), err
 		}
-		if len(contents) != 0 {
-			contents += "\n" + string(tmpbytes)
-		} else {
-			contents += string(tmpbytes)
+		if sb.Len() != 0 {
+			sb.WriteByte('\n')
 		}
+		sb.Write(tmpbytes)
 	}
-	return
+	return sb.String(), nil
 }
 
 func BaseNoExt(fpath string) string {
 	return strings.TrimSuffix(Base(fpath), Ext(fpath))
 }
 
+// PathIsExist reports whether path exists, following symlinks.
+// A dangling symlink does not exist.
 func PathIsExist(path string) bool {
-	if _, err := os.Stat(path); err == nil {
-		return true
-	}
-	return false
+	pi, _ := Inspect(path)
+	return pi.ResolvedKind() != KindNone
 }
 
+// PathIsDir reports whether path is a directory and not a symlink.
 func PathIsDir(path string) bool {
-	if PathIsSymlink(path) {
-		return false
-	}
-	if finfo, err := os.Stat(path); err == nil {
-		if finfo.IsDir() {
-			return true
-		}
-	}
-	return false
+	pi, _ := Inspect(path)
+	return pi.Kind == KindDir
 }
 
 func PathIsDirOrLinkToDir(path string) bool {
-	if PathIsDir(path) {
-		return true
-	}
-	return PathIsSymlinkDir(path)
+	pi, _ := Inspect(path)
+	return pi.ResolvedKind() == KindDir
 }
 
+// PathIsFile reports whether path exists, is not a symlink and is not a
+// directory.
 func PathIsFile(path string) bool {
-	if PathIsSymlink(path) {
-		return false
-	}
-	if finfo, err := os.Stat(path); err == nil {
-		if !finfo.IsDir() {
-			return true
-		}
-	}
-	return false
+	pi, _ := Inspect(path)
+	return pi.Exists() && pi.Kind != KindDir && pi.Kind != KindSymlink
 }
 
 func PathIsFileOrLinkToFile(path string) bool {
-	if PathIsFile(path) {
-		return true
-	}
-	return PathIsSymlinkFile(path)
+	pi, _ := Inspect(path)
+	k := pi.ResolvedKind()
+	return k != KindNone && k != KindDir
 }
 
 func PathIsSymlink(filename string) bool {
-	fileInfo, err := os.Lstat(filename)
-	if err != nil {
-		return false
-	}
-
-	return fileInfo.Mode()&os.ModeSymlink != 0
+	pi, _ := Inspect(filename)
+	return pi.Kind == KindSymlink
 }
 
 func PathIsSymlinkDir(path string) bool {
-	isSymlink := PathIsSymlink(path)
-
-	if !isSymlink {
-		return false
-	}
-
-	targetInfo, err := os.Stat(path)
-	if err != nil {
-		return false
-	}
-
-	return targetInfo.IsDir()
+	pi, _ := Inspect(path)
+	return pi.Kind == KindSymlink && pi.TargetKind == KindDir
 }
 
 func PathIsSymlinkFile(path string) bool {
-	isSymlink := PathIsSymlink(path)
-
-	if !isSymlink {
-		return false
-	}
-
-	targetInfo, err := os.Stat(path)
-	if err != nil {
-		return false
-	}
-
-	return !targetInfo.IsDir()
+	pi, _ := Inspect(path)
+	return pi.Kind == KindSymlink && pi.TargetKind != KindNone && pi.TargetKind != KindDir
 }
@@ -1,8 +1,14 @@
-//go:build !windows
-// +build !windows
+//go:build !windows && !linux
+// +build !windows,!linux
 
 package gofilepath
 
+import "errors"
+
 func getDrives() ([]string, error) {
 	return []string{"/"}, nil
 }
+
+func volumes() ([]Volume, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -1,6 +1,8 @@
 package gofilepath
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -229,3 +231,110 @@ func TestCrossPlatformScenario(t *testing.T) {
 		t.Errorf("JoinSlash with Windows relPath = %q, want %q", remoteDest, "/opt/data/sub/file.txt")
 	}
 }
+
+func TestEqualPaths(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		flavor Flavor
+		want   bool
+	}{
+		{`C:\Users`, "c:/users/", FlavorWindows, true},
+		{`\\server\share\x`, "//SERVER/share/x", FlavorWindows, true},
+		{`\\server\share`, `\server\share`, FlavorWindows, false},
+		{"/Users/Me", "/users/me", FlavorPOSIX, false},
+		{"/Users/Me", "/users/me", FlavorDarwin, true},
+		{"/tmp/caf\u00e9", "/tmp/cafe\u0301", FlavorDarwin, true},
+		{"/tmp/caf\u00e9", "/tmp/cafe\u0301", FlavorPOSIX, false},
+		{"/a/./b/", "/a/b", FlavorPOSIX, true},
+	}
+	for _, tt := range tests {
+		if got := EqualPaths(tt.a, tt.b, tt.flavor); got != tt.want {
+			t.Errorf("EqualPaths(%q, %q, %v) = %v, want %v", tt.a, tt.b, tt.flavor, got, tt.want)
+		}
+	}
+	if got, want := CanonicalKey(`C:\Users\`, FlavorWindows), "c:/users"; got != want {
+		t.Errorf("CanonicalKey = %q, want %q", got, want)
+	}
+	if got, err := RelSlash(`C:\Users\home`, `c:\Users\home\docs`); err != nil || got != "docs" {
+		t.Errorf("RelSlash across drive letter case = %q, %v, want %q", got, err, "docs")
+	}
+}
+
+func TestRelFlavor(t *testing.T) {
+	tests := []struct {
+		base, targ string
+		flavor     Flavor
+		want       string
+		wantErr    error
+	}{
+		{`C:\a\b`, `c:\A\b\c\d`, FlavorWindows, `c\d`, nil},
+		{`C:\a\b`, `C:\a\x`, FlavorWindows, `..\x`, nil},
+		{`C:\a`, `D:\b`, FlavorWindows, "", ErrDifferentVolume},
+		{`\\srv\share\a`, `\\SRV\Share\a\b`, FlavorWindows, "b", nil},
+		{`\\srv\share\a`, `\\srv\other\a`, FlavorWindows, "", ErrDifferentVolume},
+		{`\\?\C:\a`, `C:\a\b`, FlavorWindows, "b", nil},
+		{"/a/b", "/a/B", FlavorPOSIX, "../B", nil},
+		{"/a/b", "/A/b/c", FlavorDarwin, "c", nil},
+		{"a", "a", FlavorPOSIX, ".", nil},
+	}
+	for _, tt := range tests {
+		got, err := RelFlavor(tt.base, tt.targ, tt.flavor)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RelFlavor(%q, %q) error = %v, want %v", tt.base, tt.targ, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("RelFlavor(%q, %q, %v) = %q, %v, want %q", tt.base, tt.targ, tt.flavor, got, err, tt.want)
+		}
+	}
+	if _, err := RelSlash(`C:\a`, `D:\b`); !errors.Is(err, ErrDifferentVolume) {
+		t.Errorf("RelSlash across drives error = %v, want ErrDifferentVolume", err)
+	}
+	if _, err := RelFlavor("/a", "b", FlavorPOSIX); err == nil {
+		t.Errorf("RelFlavor(abs, rel) should fail")
+	}
+}
+
+func TestJoinFlavor(t *testing.T) {
+	tests := []struct {
+		flavor Flavor
+		elems  []string
+		want   string
+	}{
+		{FlavorWindows, []string{`C:\Users`, "me/AppData"}, `C:\Users\me\AppData`},
+		{FlavorWindows, []string{`\\srv\share`, "dir", "f.txt"}, `\\srv\share\dir\f.txt`},
+		{FlavorWindows, []string{"", "a", `b\..\c`}, `a\c`},
+		{FlavorWindows, []string{"", ""}, ""},
+		{FlavorPOSIX, []string{"/a", "b/", "c"}, "/a/b/c"},
+	}
+	for _, tt := range tests {
+		if got := JoinFlavor(tt.flavor, tt.elems...); got != tt.want {
+			t.Errorf("JoinFlavor(%v, %q) = %q, want %q", tt.flavor, tt.elems, got, tt.want)
+		}
+	}
+}
+
+// writeTree creates files below root from a map of relative path to content.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(root, FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -4,18 +4,6 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-func bitsToDrives(bitMap uint32) (drives []string) {
-	availableDrives := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
-
-	for i := range availableDrives {
-		if bitMap&1 == 1 {
-			drives = append(drives, availableDrives[i])
-		}
-		bitMap >>= 1
-	}
-	return
-}
-
 func getDrives() ([]string, error) {
 	narkDrives, e := windows.GetLogicalDrives()
 	if e != nil {
@@ -23,3 +11,43 @@ func getDrives() ([]string, error) {
 	}
 	return bitsToDrives(narkDrives), nil
 }
+
+// winVolumeAPI implements volumeAPI with Win32 calls.
+type winVolumeAPI struct{}
+
+func (winVolumeAPI) LogicalDrives() (uint32, error) {
+	return windows.GetLogicalDrives()
+}
+
+func (winVolumeAPI) DriveType(root string) uint32 {
+	p, err := windows.UTF16PtrFromString(root)
+	if err != nil {
+		return uint32(DriveUnknown)
+	}
+	return windows.GetDriveType(p)
+}
+
+func (winVolumeAPI) VolumeInformation(root string) (label, fileSystem string, err error) {
+	p, err := windows.UTF16PtrFromString(root)
+	if err != nil {
+		return "", "", err
+	}
+	var labelBuf, fsBuf [windows.MAX_PATH + 1]uint16
+	if err = windows.GetVolumeInformation(p, &labelBuf[0], uint32(len(labelBuf)), nil, nil, nil, &fsBuf[0], uint32(len(fsBuf))); err != nil {
+		return "", "", err
+	}
+	return windows.UTF16ToString(labelBuf[:]), windows.UTF16ToString(fsBuf[:]), nil
+}
+
+func (winVolumeAPI) DiskFreeSpace(root string) (avail, total, free uint64, err error) {
+	p, err := windows.UTF16PtrFromString(root)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	err = windows.GetDiskFreeSpaceEx(p, &avail, &total, &free)
+	return
+}
+
+func volumes() ([]Volume, error) {
+	return volumesFrom(winVolumeAPI{})
+}
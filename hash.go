@@ -0,0 +1,247 @@
+package gofilepath
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// HashAlgorithm creates the hash used by HashFile and FindDuplicates. Any
+// hash.Hash constructor fits, e.g. xxhash.New from
+// github.com/cespare/xxhash.
+type HashAlgorithm func() hash.Hash
+
+var (
+	SHA256 HashAlgorithm = sha256.New
+	SHA1   HashAlgorithm = sha1.New
+	MD5    HashAlgorithm = md5.New
+
+	// FNV64a is a fast non-cryptographic hash, for finding duplicates
+	// among trusted files.
+	FNV64a HashAlgorithm = func() hash.Hash { return fnv.New64a() }
+)
+
+// HashFile returns the hex digest of the content of path. A nil algo
+// means SHA256.
+func HashFile(path string, algo HashAlgorithm) (string, error) {
+	f, err := os.Open(FromSlash(path))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hashReader(f, algo)
+}
+
+func hashReader(r io.Reader, algo HashAlgorithm) (string, error) {
+	if algo == nil {
+		algo = SHA256
+	}
+	h := algo()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DuplicateOptions controls FindDuplicates.
+type DuplicateOptions struct {
+	// Filter selects the compared files by their path relative to their
+	// root. Directories matching Filter.Exclude are not descended into.
+	Filter Filter
+
+	// MaxDepth limits the search like the maxdeep argument of the finders:
+	// 0 looks only at the files directly in each root. Negative means
+	// unlimited.
+	MaxDepth int
+
+	// MinSize skips smaller files. Zero means 1: empty files are never
+	// reported.
+	MinSize int64
+
+	// Hash is the content hash. Nil means SHA256.
+	Hash HashAlgorithm
+
+	// Parallel is the maximum number of files hashed at the same time.
+	// Zero or one hashes sequentially.
+	Parallel int
+}
+
+// DuplicateGroup is a set of files with the same content.
+type DuplicateGroup struct {
+	Size  int64
+	Hash  string   // hex digest of the content
+	Paths []string // sorted
+}
+
+// duplicatePartialSize is how much of each file the partial hash reads.
+const duplicatePartialSize = 64 * 1024
+
+// FindDuplicates returns the groups of regular files below roots that
+// have the same content, largest files first. Candidates are narrowed
+// down by size, then by a hash of their first 64 KiB, and only then
+// hashed completely, so most files are read partially or not at all.
+//
+// Hard links to the same file, and files reached through overlapping
+// roots, are counted once: they are recognized by device and inode, or
+// with os.SameFile where no inode is available. Symlinks are not
+// followed. Unreadable files are skipped.
+func FindDuplicates(roots []string, opts DuplicateOptions) ([]DuplicateGroup, error) {
+	minSize := opts.MinSize
+	if minSize <= 0 {
+		minSize = 1
+	}
+
+	type file struct {
+		path string
+		info fs.FileInfo
+	}
+	bySize := map[int64][]file{}
+	seen := map[fileID]bool{}
+	for _, root := range roots {
+		root = FromSlash(root)
+		if _, err := os.Lstat(root); err != nil {
+			return nil, err
+		}
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || path == root {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil
+			}
+			if opts.MaxDepth >= 0 && strings.Count(rel, string(filepath.Separator)) > opts.MaxDepth {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if opts.Filter.Excluded(rel) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || !opts.Filter.Match(rel) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || info.Size() < minSize {
+				return nil
+			}
+			if id, _, _, ok := fileIdentity(info); ok {
+				if seen[id] {
+					return nil // another name of a file already seen
+				}
+				seen[id] = true
+			} else {
+				// Without an inode, compare with the files of the same
+				// size.
+				for _, f := range bySize[info.Size()] {
+					if os.SameFile(f.info, info) {
+						return nil
+					}
+				}
+			}
+			bySize[info.Size()] = append(bySize[info.Size()], file{path, info})
+			return nil
+		})
+	}
+
+	// Hash the candidates of each stage in parallel and regroup them.
+	type candidate struct {
+		size int64
+		path string
+		key  string
+	}
+	type group struct {
+		size int64
+		key  string
+	}
+	var candidates []candidate
+	for size, files := range bySize {
+		if len(files) > 1 {
+			for _, f := range files {
+				candidates = append(candidates, candidate{size: size, path: f.path})
+			}
+		}
+	}
+	stage := func(cands []candidate, partial bool) []candidate {
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, max(opts.Parallel, 1))
+		)
+		for i := range cands {
+			c := &cands[i]
+			if partial && c.size <= duplicatePartialSize {
+				continue // the full hash reads the same bytes
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				c.key = ""
+				f, err := os.Open(c.path)
+				if err != nil {
+					return
+				}
+				defer f.Close()
+				var r io.Reader = f
+				if partial {
+					r = io.LimitReader(f, duplicatePartialSize)
+				}
+				if sum, err := hashReader(r, opts.Hash); err == nil {
+					c.key = sum
+				}
+			}()
+		}
+		wg.Wait()
+		// Keep the candidates that share size and hash. After the full
+		// hash, files that could not be read have no key and are dropped.
+		count := map[group]int{}
+		for _, c := range cands {
+			count[group{c.size, c.key}]++
+		}
+		var keep []candidate
+		for _, c := range cands {
+			if (partial || c.key != "") && count[group{c.size, c.key}] > 1 {
+				keep = append(keep, c)
+			}
+		}
+		return keep
+	}
+	candidates = stage(stage(candidates, true), false)
+
+	index := map[group]int{}
+	var result []DuplicateGroup
+	for _, c := range candidates {
+		k := group{c.size, c.key}
+		i, ok := index[k]
+		if !ok {
+			i = len(result)
+			index[k] = i
+			result = append(result, DuplicateGroup{Size: c.size, Hash: c.key})
+		}
+		result[i].Paths = append(result[i].Paths, c.path)
+	}
+	for i := range result {
+		sort.Strings(result[i].Paths)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Paths[0] < result[j].Paths[0]
+	})
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHashFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(p, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		algo HashAlgorithm
+		want string
+	}{
+		{nil, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA1, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{MD5, "900150983cd24fb0d6963f7d28e17f72"},
+		{FNV64a, "e71fa2190541574b"},
+	}
+	for _, tt := range tests {
+		if got, err := HashFile(p, tt.algo); err != nil || got != tt.want {
+			t.Errorf("HashFile = %q, %v; want %q", got, err, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	big := strings.Repeat("x", duplicatePartialSize+10)
+	writeTree(t, a, map[string]string{
+		"one.txt":      "duplicate",
+		"sub/two.txt":  "duplicate",
+		"other.txt":    "different",
+		"empty1":       "",
+		"big1":         big + "A",
+		"big2":         big + "B", // same size and first 64 KiB
+		"skip/dup.txt": "duplicate",
+	})
+	writeTree(t, b, map[string]string{"three.txt": "duplicate", "empty2": "", "big3": big + "A"})
+	if err := os.Link(filepath.Join(a, "other.txt"), filepath.Join(a, "hardlink.txt")); err != nil {
+		t.Skip("hard links not supported:", err)
+	}
+
+	groups, err := FindDuplicates([]string{a, b, a}, DuplicateOptions{
+		MaxDepth: -1,
+		Parallel: 4,
+		Filter:   Filter{Exclude: []string{"skip"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	for _, g := range groups {
+		got = append(got, g.Paths)
+	}
+	want := [][]string{
+		{filepath.Join(a, "big1"), filepath.Join(b, "big3")},
+		{filepath.Join(a, "one.txt"), filepath.Join(a, "sub", "two.txt"), filepath.Join(b, "three.txt")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates = %v, want %v", got, want)
+	}
+}
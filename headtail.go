@@ -0,0 +1,247 @@
+package gofilepath
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// tailBlockSize is the size of the blocks Tail reads backwards.
+const tailBlockSize = 64 * 1024
+
+// followPollInterval is how often Follow checks the file for new data,
+// truncation and rotation.
+var followPollInterval = 250 * time.Millisecond
+
+// followTailSize is how many of the last bytes read Follow keeps to notice
+// a file truncated and refilled past its position between two polls.
+const followTailSize = 64
+
+// Head returns the first n lines of the file at path, without their line
+// terminators. A trailing "\r" is removed as well. If the file has fewer
+// than n lines, all lines are returned.
+func Head(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := make([]string, 0, n)
+	r := bufio.NewReader(f)
+	for len(lines) < n {
+		line, err := r.ReadString('\n')
+		if len(line) != 0 {
+			lines = append(lines, trimEOL(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+	return lines, nil
+}
+
+// Tail returns the last n lines of the file at path, without their line
+// terminators. The file is read backwards in blocks, so only the tail is
+// loaded into memory.
+func Tail(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := fi.Size()
+	if offset == 0 {
+		return []string{}, nil
+	}
+	var blocks [][]byte // read backwards, the last block of the file first
+	lines := 0
+	skipLastNL := true
+	for offset > 0 {
+		size := int64(tailBlockSize)
+		if size > offset {
+			size = offset
+		}
+		offset -= size
+		block := make([]byte, size)
+		if _, err = f.ReadAt(block, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		if skipLastNL {
+			// The terminator of the last line does not start a new one.
+			block = bytes.TrimSuffix(block, []byte{'\n'})
+			skipLastNL = false
+		}
+		blocks = append(blocks, block)
+		// n complete lines need a newline before the first of them,
+		// unless we reach the beginning of the file.
+		if lines += bytes.Count(block, []byte{'\n'}); lines >= n {
+			break
+		}
+	}
+
+	var sb strings.Builder
+	for i := len(blocks) - 1; i >= 0; i-- {
+		sb.Write(blocks[i])
+	}
+	parts := strings.Split(sb.String(), "\n")
+	if len(parts) > n {
+		parts = parts[len(parts)-n:]
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSuffix(parts[i], "\r")
+	}
+	return parts, nil
+}
+
+func trimEOL(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// Follow streams lines appended to the file at path to fn, like tail -F.
+// It starts at the current end of the file and runs until ctx is done or fn
+// returns an error.
+//
+// Follow survives log rotation: when the file is truncated it restarts from
+// the beginning, and when path is renamed or replaced it drains the old file
+// and reopens path once it exists again. A truncation refilled past the
+// previous end before the next poll (copytruncate) is noticed because the
+// last bytes read changed.
+//
+// Follow returns nil when ctx is done, or the error returned by fn.
+func Follow(ctx context.Context, path string, fn func(line string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	var partial []byte
+	// tail holds the last bytes before the read position.
+	tail := make([]byte, min(end, followTailSize))
+	if _, err := f.ReadAt(tail, end-int64(len(tail))); err != nil {
+		return err
+	}
+	buf := make([]byte, 32*1024)
+	// drain reads everything currently available from f and emits
+	// complete lines.
+	drain := func() error {
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				tail = append(tail, buf[:n]...)
+				tail = tail[max(len(tail)-followTailSize, 0):]
+				partial = append(partial, buf[:n]...)
+				for {
+					i := bytes.IndexByte(partial, '\n')
+					if i < 0 {
+						break
+					}
+					line := trimEOL(string(partial[:i+1]))
+					partial = partial[i+1:]
+					if err := fn(line); err != nil {
+						return err
+					}
+				}
+			}
+			if err == io.EOF || n == 0 {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+	for {
+		if f != nil {
+			cur, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			pos, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			if cur.Size() < pos || rewritten(f, pos, tail) { // truncated
+				partial, tail = partial[:0], tail[:0]
+				if _, err = f.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+			if err := drain(); err != nil {
+				return err
+			}
+			onDisk, err := os.Stat(path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			if err != nil || !os.SameFile(cur, onDisk) { // rotated
+				if err := drain(); err != nil {
+					return err
+				}
+				f.Close()
+				f = nil
+				partial, tail = partial[:0], tail[:0]
+			}
+		}
+		if f == nil {
+			if nf, err := os.Open(path); err == nil {
+				f = nf
+				continue
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rewritten reports whether the bytes of f before pos differ from tail, the
+// last bytes read there.
+func rewritten(f *os.File, pos int64, tail []byte) bool {
+	if len(tail) == 0 {
+		return false
+	}
+	b := make([]byte, len(tail))
+	if _, err := f.ReadAt(b, pos-int64(len(tail))); err != nil {
+		return false
+	}
+	return !bytes.Equal(b, tail)
+}
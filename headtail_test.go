@@ -0,0 +1,106 @@
+package gofilepath
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeadTail(t *testing.T) {
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.log")
+	var sb strings.Builder
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&sb, "line %d\r\n", i)
+	}
+	os.WriteFile(big, []byte(sb.String()), 0o644)
+	small := filepath.Join(dir, "small.log")
+	os.WriteFile(small, []byte("a\nb\nc"), 0o644)
+	empty := filepath.Join(dir, "empty.log")
+	os.WriteFile(empty, nil, 0o644)
+
+	tests := []struct {
+		name       string
+		path       string
+		n          int
+		head, tail []string
+	}{
+		{"big", big, 2, []string{"line 0", "line 1"}, []string{"line 19998", "line 19999"}},
+		{"no trailing newline", small, 2, []string{"a", "b"}, []string{"b", "c"}},
+		{"more than available", small, 10, []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{"empty", empty, 3, []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		head, err := Head(tt.path, tt.n)
+		if err != nil || !reflect.DeepEqual(head, tt.head) {
+			t.Errorf("%s: Head = %q, %v, want %q", tt.name, head, err, tt.head)
+		}
+		tail, err := Tail(tt.path, tt.n)
+		if err != nil || !reflect.DeepEqual(tail, tt.tail) {
+			t.Errorf("%s: Tail = %q, %v, want %q", tt.name, tail, err, tt.tail)
+		}
+	}
+}
+
+func TestFollow(t *testing.T) {
+	old := followPollInterval
+	followPollInterval = 10 * time.Millisecond
+	defer func() { followPollInterval = old }()
+
+	dir := t.TempDir()
+	logf := filepath.Join(dir, "app.log")
+	os.WriteFile(logf, []byte("before\n"), 0o644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lines := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, logf, func(line string) error {
+			lines <- line
+			return nil
+		})
+	}()
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("Follow line = %q, want %q", got, want)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Follow: timeout waiting for %q", want)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	f, _ := os.OpenFile(logf, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("first\n")
+	f.Close()
+	expect("first")
+
+	// rotate: rename and create a new file
+	os.Rename(logf, logf+".1")
+	os.WriteFile(logf, []byte("rotated\n"), 0o644)
+	expect("rotated")
+
+	// truncate in place
+	os.WriteFile(logf, []byte("x\n"), 0o644)
+	expect("x")
+
+	// copytruncate refilled past the old end before the next poll
+	f, _ = os.OpenFile(logf, os.O_WRONLY, 0)
+	f.WriteAt([]byte("refilled\n"), 0)
+	f.Close()
+	expect("refilled")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow returned %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package gofilepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrExecutableNotFound is returned by LookPath when no candidate is found.
+var ErrExecutableNotFound = errors.New("executable file not found in path list")
+
+// defaultPathExt is used for Windows lookups when PATHEXT is not set.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// LookPathOptions controls LookPath.
+type LookPathOptions struct {
+	// Flavor selects the list separator and lookup rules.
+	// FlavorWindows honors PathExt and ignores permission bits; the other
+	// flavors require a regular file with an executable bit set.
+	Flavor Flavor
+
+	// PathExt is the PATHEXT-style list of extensions tried for
+	// FlavorWindows lookups. Empty means $PATHEXT, or ".COM;.EXE;.BAT;.CMD"
+	// if that is not set either.
+	PathExt string
+}
+
+// pathExts returns the extensions to try for a Windows lookup.
+func (o LookPathOptions) pathExts() []string {
+	pathext := o.PathExt
+	if pathext == "" {
+		pathext = os.Getenv("PATHEXT")
+	}
+	if pathext == "" {
+		pathext = defaultPathExt
+	}
+	exts := []string{}
+	for _, e := range strings.Split(pathext, ";") {
+		if e == "" {
+			continue
+		}
+		if e[0] != '.' {
+			e = "." + e
+		}
+		exts = append(exts, e)
+	}
+	return exts
+}
+
+// candidates returns the file names to try for name in one directory.
+// Windows extensions are tried as listed and in lower case, for Windows
+// trees on case-sensitive filesystems.
+func (o LookPathOptions) candidates(name string) []string {
+	if o.Flavor.Resolve() != FlavorWindows {
+		return []string{name}
+	}
+	exts := o.pathExts()
+	names := []string{}
+	lower := strings.ToLower(name)
+	for _, e := range exts {
+		if strings.HasSuffix(lower, strings.ToLower(e)) {
+			// name already has an executable extension: try it first.
+			names = append(names, name)
+			break
+		}
+	}
+	for _, e := range exts {
+		names = append(names, name+e)
+		if l := strings.ToLower(e); l != e {
+			names = append(names, name+l)
+		}
+	}
+	return names
+}
+
+// isExecutable reports whether path can be run under flavor f.
+func isExecutable(path string, f Flavor) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	if f.Resolve() == FlavorWindows {
+		return true
+	}
+	return fi.Mode().IsRegular() && fi.Mode().Perm()&0o111 != 0
+}
+
+// LookPath searches for the executable name in the directories of pathList,
+// a PATH-style list split with the list separator of opts.Flavor. An empty
+// pathList means $PATH. Empty entries stand for the current directory.
+//
+// Unlike exec.LookPath, LookPath returns every candidate in search order:
+// the first one is what a shell would run, the rest are shadowed by it.
+// If name contains a path separator it is checked directly. When nothing is
+// found the error wraps ErrExecutableNotFound.
+func LookPath(name, pathList string, opts LookPathOptions) ([]string, error) {
+	if pathList == "" {
+		pathList = os.Getenv("PATH")
+	}
+	found := []string{}
+	seen := map[string]bool{}
+	try := func(dir string) {
+		for _, c := range opts.candidates(name) {
+			p := c
+			if dir != "" {
+				p = filepath.Join(dir, c)
+			}
+			if !seen[p] && isExecutable(p, opts.Flavor) {
+				seen[p] = true
+				found = append(found, p)
+				// Only the first matching extension runs from a directory.
+				return
+			}
+		}
+	}
+
+	seps := "/"
+	if opts.Flavor.Resolve() == FlavorWindows {
+		seps = `/\`
+	}
+	if strings.ContainsAny(name, seps) {
+		try("")
+	} else {
+		for _, dir := range SplitListFlavor(pathList, opts.Flavor) {
+			if dir == "" {
+				dir = "."
+			}
+			try(dir)
+		}
+	}
+	if len(found) == 0 {
+		return nil, &os.PathError{Op: "lookpath", Path: name, Err: ErrExecutableNotFound}
+	}
+	return found, nil
+}
+
+// Shadowed returns the executable that LookPath would run for name and the
+// candidates later in pathList that it hides.
+func Shadowed(name, pathList string, opts LookPathOptions) (winner string, shadowed []string, err error) {
+	found, err := LookPath(name, pathList, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return found[0], found[1:], nil
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitListFlavor(t *testing.T) {
+	tests := []struct {
+		list   string
+		flavor Flavor
+		want   []string
+	}{
+		{"", FlavorPOSIX, []string{}},
+		{"/bin:/usr/bin::/opt/bin", FlavorPOSIX, []string{"/bin", "/usr/bin", "", "/opt/bin"}},
+		{`C:\bin;"C:\Program Files;x\bin";D:\`, FlavorWindows, []string{`C:\bin`, `C:\Program Files;x\bin`, `D:\`}},
+	}
+	for _, tt := range tests {
+		if got := SplitListFlavor(tt.list, tt.flavor); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitListFlavor(%q, %v) = %q, want %q", tt.list, tt.flavor, got, tt.want)
+		}
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	os.Mkdir(a, 0o755)
+	os.Mkdir(b, 0o755)
+	os.WriteFile(filepath.Join(a, "tool"), []byte("#!/bin/sh\n"), 0o755)
+	os.WriteFile(filepath.Join(b, "tool"), []byte("#!/bin/sh\n"), 0o755)
+	os.WriteFile(filepath.Join(a, "noexec"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(b, "app.EXE"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(b, "app.bat"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(b, "lower.exe"), []byte("x"), 0o644)
+	os.WriteFile(filepath.Join(dir, `odd\name`), []byte("#!/bin/sh\n"), 0o755)
+
+	posix := a + ":" + b
+	got, err := LookPath("tool", posix, LookPathOptions{Flavor: FlavorPOSIX})
+	if want := []string{filepath.Join(a, "tool"), filepath.Join(b, "tool")}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookPath(tool) = %q, %v, want %q", got, err, want)
+	}
+	winner, shadowed, err := Shadowed("tool", posix, LookPathOptions{Flavor: FlavorPOSIX})
+	if err != nil || winner != filepath.Join(a, "tool") || len(shadowed) != 1 {
+		t.Errorf("Shadowed(tool) = %q, %q, %v", winner, shadowed, err)
+	}
+	// "\" is an ordinary character in POSIX names.
+	got, err = LookPath(`odd\name`, dir, LookPathOptions{Flavor: FlavorPOSIX})
+	if want := []string{filepath.Join(dir, `odd\name`)}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookPath(odd\\name) = %q, %v, want %q", got, err, want)
+	}
+	if _, err := LookPath("noexec", posix, LookPathOptions{Flavor: FlavorPOSIX}); !errors.Is(err, ErrExecutableNotFound) {
+		t.Errorf("LookPath(noexec) error = %v, want ErrExecutableNotFound", err)
+	}
+
+	windows := a + ";" + b
+	opts := LookPathOptions{Flavor: FlavorWindows, PathExt: ".COM;.EXE;.BAT"}
+	got, err = LookPath("app", windows, opts)
+	if want := []string{filepath.Join(b, "app.EXE")}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookPath(app) windows = %q, %v, want %q", got, err, want)
+	}
+	got, err = LookPath("lower", windows, opts)
+	if want := []string{filepath.Join(b, "lower.exe")}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookPath(lower) windows = %q, %v, want %q", got, err, want)
+	}
+	got, err = LookPath("app.bat", windows, opts)
+	if want := []string{filepath.Join(b, "app.bat")}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("LookPath(app.bat) windows = %q, %v, want %q", got, err, want)
+	}
+}
@@ -0,0 +1,201 @@
+package gofilepath
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Mount describes a mounted filesystem.
+type Mount struct {
+	ID           int
+	ParentID     int
+	Major, Minor int      // device number (st_dev) of the filesystem
+	Root         string   // root of the mount within the filesystem
+	MountPoint   string   // where it is mounted
+	Options      []string // per-mount options, e.g. "rw", "noatime"
+	FSType       string   // e.g. "ext4", "nfs4", "fuse.sshfs"
+	Source       string   // device or remote source, e.g. "/dev/sda1"
+	SuperOptions []string // per-superblock options
+
+	Removable bool // backed by a removable block device
+	Network   bool // remote filesystem (NFS, CIFS, sshfs, ...)
+	Pseudo    bool // no backing storage (proc, sysfs, tmpfs, cgroup, ...)
+}
+
+// Device returns the device number as "major:minor".
+func (m Mount) Device() string {
+	return fmt.Sprintf("%d:%d", m.Major, m.Minor)
+}
+
+var pseudoFSTypes = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true,
+	"cgroup2": true, "configfs": true, "debugfs": true, "devpts": true,
+	"devtmpfs": true, "efivarfs": true, "fusectl": true, "hugetlbfs": true,
+	"mqueue": true, "nsfs": true, "proc": true, "pstore": true,
+	"ramfs": true, "rpc_pipefs": true, "securityfs": true, "selinuxfs": true,
+	"sysfs": true, "tmpfs": true, "tracefs": true,
+}
+
+// imageFSTypes are read-only images and layers, such as snap packages
+// and container layers, which are not drives of their own.
+var imageFSTypes = map[string]bool{
+	"erofs": true, "fuse.squashfuse": true, "overlay": true, "squashfs": true,
+}
+
+var networkFSTypes = map[string]bool{
+	"9p": true, "afs": true, "ceph": true, "cifs": true, "davfs": true,
+	"fuse.glusterfs": true, "fuse.rclone": true, "fuse.s3fs": true,
+	"fuse.sshfs": true, "glusterfs": true, "lustre": true, "ncpfs": true,
+	"nfs": true, "nfs4": true, "smb3": true, "smbfs": true,
+}
+
+// isDrive reports whether m is listed by GetDrives and Volumes: a
+// filesystem with backing storage mounted on a directory. Pseudo
+// filesystems, read-only images and bind mounts of single files are left
+// out; "/" is always a drive.
+func (m Mount) isDrive() bool {
+	if m.MountPoint == "/" {
+		return true
+	}
+	if m.Pseudo || imageFSTypes[m.FSType] {
+		return false
+	}
+	fi, err := os.Stat(m.MountPoint)
+	return err == nil && fi.IsDir()
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space, ...) used
+// in /proc/self/mountinfo.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// ParseMountInfo parses the format of /proc/<pid>/mountinfo, described in
+// proc(5). Removable is not set because it needs sysfs; see Mounts.
+func ParseMountInfo(r io.Reader) ([]Mount, error) {
+	mounts := []Mount{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineno := 0
+	for sc.Scan() {
+		lineno++
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		m, err := parseMountInfoLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("mountinfo line %d: %w", lineno, err)
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, sc.Err()
+}
+
+func parseMountInfoLine(line string) (m Mount, err error) {
+	fields := strings.Fields(line)
+	sep := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			sep = i
+			break
+		}
+	}
+	if len(fields) < 6 || sep < 0 || len(fields) < sep+3 {
+		return m, errors.New("malformed line")
+	}
+	if m.ID, err = strconv.Atoi(fields[0]); err != nil {
+		return m, err
+	}
+	if m.ParentID, err = strconv.Atoi(fields[1]); err != nil {
+		return m, err
+	}
+	majmin := strings.SplitN(fields[2], ":", 2)
+	if len(majmin) != 2 {
+		return m, fmt.Errorf("bad device %q", fields[2])
+	}
+	if m.Major, err = strconv.Atoi(majmin[0]); err != nil {
+		return m, err
+	}
+	if m.Minor, err = strconv.Atoi(majmin[1]); err != nil {
+		return m, err
+	}
+	m.Root = unescapeMountField(fields[3])
+	m.MountPoint = unescapeMountField(fields[4])
+	m.Options = strings.Split(fields[5], ",")
+	m.FSType = fields[sep+1]
+	m.Source = unescapeMountField(fields[sep+2])
+	if len(fields) > sep+3 {
+		m.SuperOptions = strings.Split(fields[sep+3], ",")
+	}
+	m.Pseudo = pseudoFSTypes[m.FSType]
+	m.Network = networkFSTypes[m.FSType] || strings.HasPrefix(m.Source, "//")
+	return m, nil
+}
+
+// mountOf returns the mount that contains the absolute, symlink-free path.
+// When several mounts share a mount point, the last one (on top) wins.
+func mountOf(mounts []Mount, path string) (Mount, bool) {
+	best := -1
+	for i, m := range mounts {
+		if !pathWithin(path, m.MountPoint) {
+			continue
+		}
+		if best < 0 || len(m.MountPoint) >= len(mounts[best].MountPoint) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Mount{}, false
+	}
+	return mounts[best], true
+}
+
+// pathWithin reports whether path equals dir or lies below it.
+// Both must be clean absolute paths.
+func pathWithin(path, dir string) bool {
+	if path == dir || dir == "/" {
+		return true
+	}
+	return strings.HasPrefix(path, dir+"/")
+}
+
+// MountPointOf returns the mount that path lives on. Symlinks in path are
+// resolved first.
+func MountPointOf(path string) (Mount, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Mount{}, err
+	}
+	if abs, err = filepath.EvalSymlinks(abs); err != nil {
+		return Mount{}, err
+	}
+	mounts, err := Mounts()
+	if err != nil {
+		return Mount{}, err
+	}
+	if m, ok := mountOf(mounts, abs); ok {
+		return m, nil
+	}
+	return Mount{}, fmt.Errorf("no mount found for %s", path)
+}
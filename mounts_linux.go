@@ -0,0 +1,133 @@
+package gofilepath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Mounts returns the filesystems mounted in the current mount namespace,
+// read from /proc/self/mountinfo.
+func Mounts() ([]Mount, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	mounts, err := ParseMountInfo(f)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mounts {
+		mounts[i].Removable = blockDeviceRemovable(mounts[i].Major, mounts[i].Minor)
+	}
+	return mounts, nil
+}
+
+// blockDeviceRemovable reads the sysfs removable flag of a block device.
+// Partitions do not have the flag, so the parent disk is checked too.
+func blockDeviceRemovable(major, minor int) bool {
+	dev, err := filepath.EvalSymlinks(fmt.Sprintf("/sys/dev/block/%d:%d", major, minor))
+	if err != nil {
+		return false
+	}
+	for _, p := range []string{dev, filepath.Dir(dev)} {
+		if b, err := os.ReadFile(filepath.Join(p, "removable")); err == nil {
+			return strings.TrimSpace(string(b)) == "1"
+		}
+	}
+	return false
+}
+
+// getDrives returns the mount points of the filesystems that have backing
+// storage (see Mount.isDrive), falling back to "/" if /proc is not
+// available.
+func getDrives() ([]string, error) {
+	mounts, err := Mounts()
+	if err != nil {
+		return []string{"/"}, nil
+	}
+	drives := []string{}
+	seen := map[string]bool{}
+	for _, m := range mounts {
+		if !m.isDrive() || seen[m.MountPoint] {
+			continue
+		}
+		seen[m.MountPoint] = true
+		drives = append(drives, m.MountPoint)
+	}
+	if len(drives) == 0 {
+		drives = append(drives, "/")
+	}
+	return drives, nil
+}
+
+// volumes builds the volume list from the mounts GetDrives lists.
+func volumes() ([]Volume, error) {
+	mounts, err := Mounts()
+	if err != nil {
+		return nil, err
+	}
+	labels := diskLabels()
+	vols := []Volume{}
+	seen := map[string]bool{}
+	for _, m := range mounts {
+		if !m.isDrive() || seen[m.MountPoint] {
+			continue
+		}
+		seen[m.MountPoint] = true
+		v := Volume{Root: m.MountPoint, FileSystem: m.FSType, Type: DriveFixed}
+		switch {
+		case m.Network:
+			v.Type = DriveRemote
+		case m.FSType == "iso9660" || m.FSType == "udf":
+			v.Type = DriveCDROM
+		case m.Removable:
+			v.Type = DriveRemovable
+		}
+		if dev, err := filepath.EvalSymlinks(m.Source); err == nil {
+			v.Label = labels[dev]
+		}
+		if du, err := diskUsage(m.MountPoint); err == nil {
+			v.TotalBytes, v.FreeBytes, v.AvailBytes = du.TotalBytes, du.FreeBytes, du.AvailBytes
+		}
+		vols = append(vols, v)
+	}
+	return vols, nil
+}
+
+// diskLabels maps device paths to the labels udev publishes in
+// /dev/disk/by-label.
+func diskLabels() map[string]string {
+	labels := map[string]string{}
+	entries, err := os.ReadDir("/dev/disk/by-label")
+	if err != nil {
+		return labels
+	}
+	for _, e := range entries {
+		dev, err := filepath.EvalSymlinks(filepath.Join("/dev/disk/by-label", e.Name()))
+		if err != nil {
+			continue
+		}
+		labels[dev] = unescapeUdev(e.Name())
+	}
+	return labels
+}
+
+// unescapeUdev decodes the \xHH escapes udev uses in link names.
+func unescapeUdev(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) && s[i+1] == 'x' {
+			if v, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				sb.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package gofilepath
+
+import "errors"
+
+// Mounts is only implemented on Linux.
+func Mounts() ([]Mount, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,82 @@
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw,errors=remount-ro
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw
+24 22 0:22 / /run rw,nosuid,nodev shared:5 - tmpfs tmpfs rw,size=1638400k,mode=755
+40 22 8:17 / /media/usb\040stick rw,nosuid,nodev shared:30 - vfat /dev/sdb1 rw,fmask=0022
+41 22 0:45 / /mnt/share rw,relatime shared:31 - nfs4 server:/export rw,vers=4.2
+42 41 0:46 / /mnt/share/sub rw,relatime - cifs //nas/sub rw
+`
+
+func TestParseMountInfo(t *testing.T) {
+	mounts, err := ParseMountInfo(strings.NewReader(sampleMountInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 6 {
+		t.Fatalf("ParseMountInfo returned %d mounts, want 6", len(mounts))
+	}
+	root := mounts[0]
+	if root.MountPoint != "/" || root.FSType != "ext4" || root.Source != "/dev/sda1" || root.Device() != "8:1" || root.Pseudo || root.Network {
+		t.Errorf("root mount = %+v", root)
+	}
+	if !mounts[1].Pseudo || !mounts[2].Pseudo {
+		t.Errorf("proc and tmpfs should be pseudo")
+	}
+	if mounts[3].MountPoint != "/media/usb stick" {
+		t.Errorf("escaped mount point = %q", mounts[3].MountPoint)
+	}
+	if !mounts[4].Network || !mounts[5].Network {
+		t.Errorf("nfs4 and cifs should be network mounts")
+	}
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/", "/"},
+		{"/home/user", "/"},
+		{"/proc/self", "/proc"},
+		{"/mnt/share/file", "/mnt/share"},
+		{"/mnt/share/sub/file", "/mnt/share/sub"},
+		{"/mnt/shared", "/"},
+	}
+	for _, tt := range tests {
+		m, ok := mountOf(mounts, tt.path)
+		if !ok || m.MountPoint != tt.want {
+			t.Errorf("mountOf(%q) = %q, %v, want %q", tt.path, m.MountPoint, ok, tt.want)
+		}
+	}
+
+	if _, err := ParseMountInfo(strings.NewReader("garbage line\n")); err == nil {
+		t.Errorf("ParseMountInfo(garbage) should fail")
+	}
+}
+
+func TestMountIsDrive(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hosts")
+	os.WriteFile(file, nil, 0o644)
+	tests := []struct {
+		m    Mount
+		want bool
+	}{
+		{Mount{MountPoint: "/", FSType: "overlay"}, true},
+		{Mount{MountPoint: dir, FSType: "ext4"}, true},
+		{Mount{MountPoint: dir, FSType: "tmpfs", Pseudo: true}, false},
+		{Mount{MountPoint: dir, FSType: "squashfs"}, false},
+		{Mount{MountPoint: dir, FSType: "overlay"}, false},
+		{Mount{MountPoint: file, FSType: "ext4"}, false}, // bind-mounted file
+	}
+	for _, tt := range tests {
+		if got := tt.m.isDrive(); got != tt.want {
+			t.Errorf("%s on %s: isDrive = %v, want %v", tt.m.FSType, tt.m.MountPoint, got, tt.want)
+		}
+	}
+}
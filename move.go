@@ -0,0 +1,181 @@
+package gofilepath
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MoveOptions controls Move.
+type MoveOptions struct {
+	// Overwrite replaces an existing dst. Without it Move fails with
+	// ErrDestinationExists.
+	Overwrite bool
+
+	// Progress, if not nil, is called while data is copied across
+	// devices.
+	Progress func(CopyProgress)
+}
+
+// moveRename is os.Rename, replaceable in tests to simulate a rename
+// across devices.
+var moveRename = os.Rename
+
+// Move moves the file, symlink or directory src to dst. It renames src
+// when possible. When src and dst are on different filesystems (EXDEV,
+// ERROR_NOT_SAME_DEVICE) it copies src with all metadata (see
+// PreserveAll), flushes the copy to disk and then removes src.
+//
+// With opts.Overwrite an existing dst is replaced as a whole, a non-empty
+// directory included, on the same device and across devices alike. A
+// directory and a non-directory never replace each other: that fails with
+// ErrDestinationExists. On the same device a file dst is replaced
+// atomically by the rename. A directory dst, and any dst after a copy
+// across devices, is set aside under a temporary name until the move is
+// complete, so it is restored if the move fails. If dst is a hard link to
+// src, Overwrite just removes src.
+//
+// A single file is never left in both or neither place: it is copied to a
+// temporary name and renamed into place, and if src cannot be removed
+// afterwards the copy is removed again. A directory is copied completely
+// under a temporary name before it replaces dst; if removing src fails
+// after that, the returned error says so and both trees exist.
+func Move(src, dst string, opts MoveOptions) error {
+	src, dst = FromSlash(src), FromSlash(dst)
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	dfi, err := os.Lstat(dst)
+	switch {
+	case err == nil && sameDirEntry(src, dst, fi, dfi):
+		// dst names src itself, e.g. differing only in case on a
+		// case-insensitive filesystem: only a rename can handle that.
+		return moveRename(src, dst)
+	case err == nil && (!opts.Overwrite || fi.IsDir() != dfi.IsDir()):
+		return &os.LinkError{Op: "move", Old: src, New: dst, Err: ErrDestinationExists}
+	case err == nil && os.SameFile(fi, dfi):
+		// A hard link: rename(2) would leave both names in place.
+		return os.Remove(src)
+	case err != nil && !isNotExist(err):
+		return err
+	}
+	dstExists := err == nil
+
+	var old string
+	setAside := func() error {
+		if !dstExists || old != "" {
+			return nil
+		}
+		old = tempName(dst)
+		if err := os.Rename(dst, old); err != nil {
+			old = ""
+			return err
+		}
+		return nil
+	}
+	restore := func() {
+		if old != "" {
+			os.Rename(old, dst)
+		}
+	}
+	done := func() {
+		if old != "" {
+			os.RemoveAll(old)
+		}
+	}
+
+	// rename(2) replaces a file atomically, but not a non-empty directory.
+	if fi.IsDir() {
+		if err := setAside(); err != nil {
+			return err
+		}
+	}
+	if err = moveRename(src, dst); err == nil {
+		done()
+		return nil
+	} else if !isCrossDevice(err) {
+		restore()
+		return err
+	}
+	c := &copier{
+		opts:  CopyOptions{Conflict: ConflictOverwrite, Preserve: PreserveAll, Progress: opts.Progress},
+		fsync: true,
+	}
+	tmp := tempName(dst)
+	if err := c.copy(src, tmp, "", fi, nil); err != nil {
+		os.RemoveAll(tmp)
+		restore()
+		return err
+	}
+	if err := setAside(); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.RemoveAll(tmp)
+		restore()
+		return err
+	}
+	syncDir(filepath.Dir(dst))
+
+	if !fi.IsDir() {
+		if err := os.Remove(src); err != nil {
+			os.Remove(dst)
+			restore()
+			return err
+		}
+		done()
+		return nil
+	}
+	done()
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("move %s: copied to %s, but removing the source failed: %w", src, dst, err)
+	}
+	return nil
+}
+
+// sameDirEntry reports whether src and dst, described by fi and dfi, are
+// one directory entry: the same path, or names in the same directory that
+// differ only in case on a case-insensitive filesystem. Hard links are
+// distinct entries.
+func sameDirEntry(src, dst string, fi, dfi fs.FileInfo) bool {
+	if !os.SameFile(fi, dfi) {
+		return false
+	}
+	absSrc, err1 := filepath.Abs(src)
+	absDst, err2 := filepath.Abs(dst)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	if absSrc == absDst {
+		return true
+	}
+	dir, name := filepath.Split(absSrc)
+	if filepath.Clean(dir) != filepath.Dir(absDst) || !strings.EqualFold(name, filepath.Base(absDst)) {
+		return false
+	}
+	// Hard links on a case-sensitive filesystem are both listed as given.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	listed := 0
+	for _, e := range entries {
+		if e.Name() == name || e.Name() == filepath.Base(absDst) {
+			listed++
+		}
+	}
+	return listed < 2
+}
+
+// syncDir flushes the directory entries of dir to disk where the platform
+// allows it, so a rename into dir survives a crash.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package gofilepath
+
+// isCrossDevice reports no cross-device renames on platforms without
+// EXDEV; Move then returns the rename error as is.
+func isCrossDevice(err error) bool {
+	return false
+}
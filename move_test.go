@@ -0,0 +1,175 @@
+//go:build unix
+
+package gofilepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// crossDevice makes Move behave as if src and dst were on different
+// filesystems for the duration of the test.
+func crossDevice(t *testing.T) {
+	moveRename = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { moveRename = os.Rename })
+}
+
+func TestMove(t *testing.T) {
+	for _, cross := range []bool{false, true} {
+		t.Run(map[bool]string{false: "rename", true: "copy"}[cross], func(t *testing.T) {
+			if cross {
+				crossDevice(t)
+			}
+			dir := t.TempDir()
+			writeTree(t, dir, map[string]string{"f.txt": "file", "tree/a": "a", "tree/sub/b": "b", "busy": "x"})
+			mtime := time.Unix(1600000000, 0)
+			os.Chtimes(filepath.Join(dir, "f.txt"), mtime, mtime)
+
+			if err := Move(filepath.Join(dir, "f.txt"), filepath.Join(dir, "g.txt"), MoveOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if ok, _ := Exists(filepath.Join(dir, "f.txt")); ok {
+				t.Error("source file still exists")
+			}
+			if fi, err := os.Stat(filepath.Join(dir, "g.txt")); err != nil || !fi.ModTime().Equal(mtime) {
+				t.Errorf("moved file: %v, %v", fi, err)
+			}
+
+			if err := Move(filepath.Join(dir, "tree"), filepath.Join(dir, "moved"), MoveOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, filepath.Join(dir, "moved", "sub", "b")); got != "b" {
+				t.Errorf("moved/sub/b = %q", got)
+			}
+			if ok, _ := Exists(filepath.Join(dir, "tree")); ok {
+				t.Error("source tree still exists")
+			}
+
+			err := Move(filepath.Join(dir, "g.txt"), filepath.Join(dir, "busy"), MoveOptions{})
+			if !errors.Is(err, ErrDestinationExists) {
+				t.Errorf("Move onto an existing file: err = %v", err)
+			}
+			if err := Move(filepath.Join(dir, "g.txt"), filepath.Join(dir, "busy"), MoveOptions{Overwrite: true}); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, filepath.Join(dir, "busy")); got != "file" {
+				t.Errorf("busy = %q", got)
+			}
+
+			// Overwrite replaces a non-empty directory on either path.
+			writeTree(t, dir, map[string]string{"new/a": "new", "full/old": "old", "full/sub/x": "x"})
+			if err := Move(filepath.Join(dir, "new"), filepath.Join(dir, "full"), MoveOptions{Overwrite: true}); err != nil {
+				t.Fatal(err)
+			}
+			entries, _ := os.ReadDir(filepath.Join(dir, "full"))
+			if len(entries) != 1 || readFile(t, filepath.Join(dir, "full", "a")) != "new" {
+				t.Errorf("full has %d entries after the overwrite", len(entries))
+			}
+			err = Move(filepath.Join(dir, "busy"), filepath.Join(dir, "full"), MoveOptions{Overwrite: true})
+			if !errors.Is(err, ErrDestinationExists) {
+				t.Errorf("Move of a file onto a directory: err = %v", err)
+			}
+			if got := readFile(t, filepath.Join(dir, "busy")); got != "file" {
+				t.Errorf("busy = %q after the failed move", got)
+			}
+		})
+	}
+}
+
+func TestMoveKeepsSourceOnFailure(t *testing.T) {
+	crossDevice(t)
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"src/f": "data"})
+	ro := filepath.Join(dir, "ro")
+	os.Mkdir(ro, 0o555)
+	defer os.Chmod(ro, 0o755)
+	if f, err := os.Create(filepath.Join(ro, "probe")); err == nil {
+		f.Close()
+		t.Skip("running with permissions that ignore directory modes")
+	}
+
+	if err := Move(filepath.Join(dir, "src", "f"), filepath.Join(ro, "f"), MoveOptions{}); err == nil {
+		t.Fatal("Move into a read-only directory succeeded")
+	}
+	if got := readFile(t, filepath.Join(dir, "src", "f")); got != "data" {
+		t.Errorf("source = %q", got)
+	}
+	entries, _ := os.ReadDir(ro)
+	if len(entries) != 0 {
+		t.Errorf("destination directory has %d entries", len(entries))
+	}
+}
+
+func TestMoveRestoresDestinationOnFailure(t *testing.T) {
+	crossDevice(t)
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"src/f": "new", "dst": "old"})
+	ro := filepath.Join(dir, "src")
+	os.Chmod(ro, 0o555)
+	defer os.Chmod(ro, 0o755)
+	if err := os.WriteFile(filepath.Join(ro, "probe"), nil, 0o644); err == nil {
+		t.Skip("running with permissions that ignore directory modes")
+	}
+
+	if err := Move(filepath.Join(ro, "f"), filepath.Join(dir, "dst"), MoveOptions{Overwrite: true}); err == nil {
+		t.Fatal("Move out of a read-only directory succeeded")
+	}
+	if got := readFile(t, filepath.Join(ro, "f")); got != "new" {
+		t.Errorf("source = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "dst")); got != "old" {
+		t.Errorf("destination = %q, want the old content", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("%d entries left in the directory, want 2", len(entries))
+	}
+}
+
+func TestMoveOntoHardLink(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a": "data"})
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.Link(a, b); err != nil {
+		t.Skip("no hard links:", err)
+	}
+	if err := Move(a, b, MoveOptions{}); !errors.Is(err, ErrDestinationExists) {
+		t.Errorf("Move onto a hard link: err = %v, want ErrDestinationExists", err)
+	}
+	if err := Move(a, b, MoveOptions{Overwrite: true}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := LExists(a); ok {
+		t.Error("source still exists")
+	}
+	if got := readFile(t, b); got != "data" {
+		t.Errorf("b = %q", got)
+	}
+}
+
+func TestMoveReplacesFileAtomically(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"new": "new", "dst": "old"})
+	dst := filepath.Join(dir, "dst")
+	moveRename = func(oldpath, newpath string) error {
+		if _, err := os.Lstat(dst); err != nil {
+			t.Errorf("dst was gone before the rename: %v", err)
+		}
+		return os.Rename(oldpath, newpath)
+	}
+	t.Cleanup(func() { moveRename = os.Rename })
+	if err := Move(filepath.Join(dir, "new"), dst, MoveOptions{Overwrite: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, dst); got != "new" {
+		t.Errorf("dst = %q", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("%d entries left, want 1", len(entries))
+	}
+}
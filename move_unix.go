@@ -0,0 +1,14 @@
+//go:build unix
+
+package gofilepath
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether err is the failure of a rename between
+// filesystems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,13 @@
+package gofilepath
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isCrossDevice reports whether err is the failure of a rename between
+// volumes.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE)
+}
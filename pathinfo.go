@@ -0,0 +1,115 @@
+package gofilepath
+
+import (
+	"io/fs"
+	"os"
+)
+
+// PathKind is the type of a filesystem entry.
+type PathKind int
+
+const (
+	KindNone    PathKind = iota // entry does not exist or could not be inspected
+	KindFile                    // regular file
+	KindDir                     // directory
+	KindSymlink                 // symbolic link
+	KindSocket                  // Unix domain socket
+	KindFifo                    // named pipe
+	KindDevice                  // block or character device
+	KindOther                   // anything else (e.g. Windows irregular files)
+)
+
+var pathKindNames = [...]string{
+	KindNone:    "none",
+	KindFile:    "file",
+	KindDir:     "dir",
+	KindSymlink: "symlink",
+	KindSocket:  "socket",
+	KindFifo:    "fifo",
+	KindDevice:  "device",
+	KindOther:   "other",
+}
+
+func (k PathKind) String() string {
+	if k >= 0 && int(k) < len(pathKindNames) {
+		return pathKindNames[k]
+	}
+	return "unknown"
+}
+
+// KindOf returns the PathKind for a file mode.
+func KindOf(mode fs.FileMode) PathKind {
+	switch {
+	case mode.IsRegular():
+		return KindFile
+	case mode.IsDir():
+		return KindDir
+	case mode&fs.ModeSymlink != 0:
+		return KindSymlink
+	case mode&fs.ModeSocket != 0:
+		return KindSocket
+	case mode&fs.ModeNamedPipe != 0:
+		return KindFifo
+	case mode&fs.ModeDevice != 0, mode&fs.ModeCharDevice != 0:
+		return KindDevice
+	}
+	return KindOther
+}
+
+// PathInfo describes a filesystem entry as returned by Inspect.
+type PathInfo struct {
+	Path string
+	Kind PathKind    // kind of the entry itself (Lstat)
+	Info fs.FileInfo // Lstat result, nil if Err is set
+
+	// The following fields are only set when Kind is KindSymlink.
+	LinkTarget string      // raw link target as returned by os.Readlink
+	TargetKind PathKind    // kind of the final target (Stat), KindNone if dangling
+	TargetInfo fs.FileInfo // Stat result, nil if the target cannot be reached
+	Dangling   bool        // target does not exist or the link loops
+	TargetErr  error       // error from Stat, if any
+
+	Err error // error from Lstat, if any
+}
+
+// Exists reports whether the entry exists. A dangling symlink exists.
+func (p PathInfo) Exists() bool {
+	return p.Err == nil && p.Kind != KindNone
+}
+
+// ResolvedKind returns the kind of the entry after following a symlink.
+func (p PathInfo) ResolvedKind() PathKind {
+	if p.Kind == KindSymlink {
+		return p.TargetKind
+	}
+	return p.Kind
+}
+
+// Inspect classifies path with a single Lstat, plus one Readlink and one
+// Stat when path is a symlink. The returned error is the Lstat error, also
+// stored in PathInfo.Err; problems resolving a symlink target are reported
+// through Dangling and TargetErr instead.
+func Inspect(path string) (PathInfo, error) {
+	pi := PathInfo{Path: path}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		pi.Err = err
+		return pi, err
+	}
+	pi.Info = fi
+	pi.Kind = KindOf(fi.Mode())
+	if pi.Kind != KindSymlink {
+		return pi, nil
+	}
+
+	pi.LinkTarget, _ = os.Readlink(path)
+	ti, err := os.Stat(path)
+	if err != nil {
+		pi.TargetErr = err
+		pi.Dangling = isNotExist(err) || isSymlinkLoop(err)
+		return pi, nil
+	}
+	pi.TargetInfo = ti
+	pi.TargetKind = KindOf(ti.Mode())
+	return pi, nil
+}
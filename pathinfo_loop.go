@@ -0,0 +1,14 @@
+//go:build unix || windows
+
+package gofilepath
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isSymlinkLoop reports whether err is the failure to resolve a symlink
+// that points back to itself.
+func isSymlinkLoop(err error) bool {
+	return errors.Is(err, syscall.ELOOP)
+}
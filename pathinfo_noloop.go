@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package gofilepath
+
+// isSymlinkLoop reports no symlink loops where the platform has no ELOOP.
+func isSymlinkLoop(err error) bool {
+	return false
+}
@@ -0,0 +1,81 @@
+//go:build unix
+
+package gofilepath
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, []byte("x"), 0o644)
+	sub := filepath.Join(dir, "sub")
+	os.Mkdir(sub, 0o755)
+	linkFile := filepath.Join(dir, "link-file")
+	os.Symlink(file, linkFile)
+	linkDir := filepath.Join(dir, "link-dir")
+	os.Symlink("sub", linkDir)
+	dangling := filepath.Join(dir, "dangling")
+	os.Symlink("missing", dangling)
+	fifo := filepath.Join(dir, "fifo")
+	if err := unix.Mkfifo(fifo, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		path       string
+		kind       PathKind
+		target     PathKind
+		linkTarget string
+		dangling   bool
+	}{
+		{file, KindFile, KindNone, "", false},
+		{sub, KindDir, KindNone, "", false},
+		{linkFile, KindSymlink, KindFile, file, false},
+		{linkDir, KindSymlink, KindDir, "sub", false},
+		{dangling, KindSymlink, KindNone, "missing", true},
+		{fifo, KindFifo, KindNone, "", false},
+		{sock, KindSocket, KindNone, "", false},
+		{"/dev/null", KindDevice, KindNone, "", false},
+	}
+	for _, tt := range tests {
+		pi, err := Inspect(tt.path)
+		if err != nil {
+			t.Errorf("Inspect(%q) error: %v", tt.path, err)
+			continue
+		}
+		if pi.Kind != tt.kind || pi.TargetKind != tt.target || pi.LinkTarget != tt.linkTarget || pi.Dangling != tt.dangling {
+			t.Errorf("Inspect(%q) = kind %v target %v link %q dangling %v, want %v %v %q %v",
+				tt.path, pi.Kind, pi.TargetKind, pi.LinkTarget, pi.Dangling, tt.kind, tt.target, tt.linkTarget, tt.dangling)
+		}
+	}
+
+	if pi, err := Inspect(filepath.Join(dir, "nope")); err == nil || pi.Exists() {
+		t.Errorf("Inspect(missing) = %+v, %v, want error", pi, err)
+	}
+
+	if !PathIsSymlinkDir(linkDir) || PathIsDir(linkDir) || !PathIsDirOrLinkToDir(linkDir) {
+		t.Errorf("directory predicates wrong for %q", linkDir)
+	}
+	if !PathIsSymlinkFile(linkFile) || PathIsFile(linkFile) || !PathIsFileOrLinkToFile(linkFile) {
+		t.Errorf("file predicates wrong for %q", linkFile)
+	}
+	if PathIsExist(dangling) || !PathIsSymlink(dangling) {
+		t.Errorf("dangling predicates wrong for %q", dangling)
+	}
+	if !PathIsUnixSocket(sock) || PathIsUnixSocket(file) {
+		t.Errorf("PathIsUnixSocket wrong")
+	}
+}
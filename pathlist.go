@@ -0,0 +1,142 @@
+package gofilepath
+
+import (
+	"os"
+	"strings"
+)
+
+// PathList is an ordered list of directories parsed from a PATH-like
+// variable such as PATH, GOPATH, LD_LIBRARY_PATH or PYTHONPATH.
+//
+// Entries are compared per Flavor: case-insensitively and ignoring the
+// separator style on Windows, exactly elsewhere. Trailing separators are
+// ignored in both cases.
+type PathList struct {
+	Flavor  Flavor
+	entries []string
+}
+
+// ParsePathList parses value with the list separator of f.
+// Quoted entries are supported for FlavorWindows.
+func ParsePathList(value string, f Flavor) *PathList {
+	return &PathList{Flavor: f, entries: SplitListFlavor(value, f)}
+}
+
+// PathListFromEnv parses the environment variable key with the host flavor.
+func PathListFromEnv(key string) *PathList {
+	return ParsePathList(os.Getenv(key), FlavorHost)
+}
+
+// Entries returns a copy of the entries in order.
+func (l *PathList) Entries() []string {
+	return append([]string{}, l.entries...)
+}
+
+// Len returns the number of entries.
+func (l *PathList) Len() int {
+	return len(l.entries)
+}
+
+// key returns the comparison key of entry for the list flavor.
+func (l *PathList) key(entry string) string {
+	if l.Flavor.Resolve() == FlavorWindows {
+		k := strings.ToLower(NormalizeSeparators(entry))
+		if t := strings.TrimRight(k, "/"); t != "" {
+			k = t
+		}
+		return k
+	}
+	if t := strings.TrimRight(entry, "/"); t != "" {
+		return t
+	}
+	return entry
+}
+
+// Contains reports whether dir is in the list.
+func (l *PathList) Contains(dir string) bool {
+	k := l.key(dir)
+	for _, e := range l.entries {
+		if l.key(e) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Prepend inserts dirs at the front of the list, keeping their order.
+// Existing occurrences of dirs are removed first, so they move to the front.
+func (l *PathList) Prepend(dirs ...string) *PathList {
+	l.Remove(dirs...)
+	l.entries = append(append([]string{}, dirs...), l.entries...)
+	return l
+}
+
+// Append adds dirs at the end of the list, keeping their order.
+// Existing occurrences of dirs are removed first, so they move to the end.
+func (l *PathList) Append(dirs ...string) *PathList {
+	l.Remove(dirs...)
+	l.entries = append(l.entries, dirs...)
+	return l
+}
+
+// Remove deletes every occurrence of dirs from the list.
+func (l *PathList) Remove(dirs ...string) *PathList {
+	drop := make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		drop[l.key(d)] = true
+	}
+	return l.filter(func(e string) bool { return !drop[l.key(e)] })
+}
+
+// Dedup removes repeated entries, keeping the first occurrence.
+func (l *PathList) Dedup() *PathList {
+	seen := make(map[string]bool, len(l.entries))
+	return l.filter(func(e string) bool {
+		k := l.key(e)
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	})
+}
+
+// RemoveNonexistent removes entries that are not existing directories.
+// Empty entries, which stand for the current directory, are removed too.
+func (l *PathList) RemoveNonexistent() *PathList {
+	return l.filter(func(e string) bool {
+		if e == "" {
+			return false
+		}
+		ok, _ := IsDir(e)
+		return ok
+	})
+}
+
+func (l *PathList) filter(keep func(string) bool) *PathList {
+	kept := l.entries[:0]
+	for _, e := range l.entries {
+		if keep(e) {
+			kept = append(kept, e)
+		}
+	}
+	l.entries = kept
+	return l
+}
+
+// String joins the entries with the list separator of the flavor.
+// On Windows, entries containing the separator are quoted.
+func (l *PathList) String() string {
+	sep := string(l.Flavor.ListSeparator())
+	if l.Flavor.Resolve() != FlavorWindows {
+		return strings.Join(l.entries, sep)
+	}
+	quoted := make([]string, len(l.entries))
+	for i, e := range l.entries {
+		if strings.Contains(e, sep) {
+			e = `"` + e + `"`
+		}
+		quoted[i] = e
+	}
+	return strings.Join(quoted, sep)
+}
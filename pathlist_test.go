@@ -0,0 +1,39 @@
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPathList(t *testing.T) {
+	l := ParsePathList("/usr/bin:/bin:/usr/bin/:/opt/bin", FlavorPOSIX)
+	l.Dedup()
+	if want := []string{"/usr/bin", "/bin", "/opt/bin"}; !reflect.DeepEqual(l.Entries(), want) {
+		t.Errorf("Dedup = %q, want %q", l.Entries(), want)
+	}
+	l.Prepend("/opt/bin").Append("/sbin").Remove("/bin")
+	if got, want := l.String(), "/opt/bin:/usr/bin:/sbin"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+	if !l.Contains("/usr/bin/") || l.Contains("/bin") {
+		t.Errorf("Contains wrong for %q", l.String())
+	}
+
+	w := ParsePathList(`C:\Windows;"C:\My;Tools";c:\windows\`, FlavorWindows)
+	if !w.Contains("C:/WINDOWS") {
+		t.Errorf("Contains should fold case on Windows: %q", w.Entries())
+	}
+	w.Dedup()
+	if got, want := w.String(), `C:\Windows;"C:\My;Tools"`; got != want {
+		t.Errorf("Windows String = %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	sep := string(os.PathListSeparator)
+	e := ParsePathList(dir+sep+sep+filepath.Join(dir, "missing"), FlavorHost).RemoveNonexistent()
+	if want := []string{dir}; !reflect.DeepEqual(e.Entries(), want) {
+		t.Errorf("RemoveNonexistent = %q, want %q", e.Entries(), want)
+	}
+}
@@ -0,0 +1,108 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrDifferentVolume is returned when a relative path is requested between
+// paths on different drives or UNC shares.
+var ErrDifferentVolume = errors.New("paths are on different volumes")
+
+// SplitVolumeFlavor splits p into its volume and the rest, using the
+// syntax of flavor. For FlavorWindows the volume is a drive ("C:") or a UNC
+// share ("//server/share"), returned with '/' separators; "\\?\" prefixes
+// are removed. Other flavors have no volumes.
+//
+//	SplitVolumeFlavor(`\\srv\share\dir`, FlavorWindows) → "//srv/share", "/dir"
+func SplitVolumeFlavor(p string, flavor Flavor) (volume, rest string) {
+	if flavor.Resolve() != FlavorWindows {
+		return "", p
+	}
+	p = NormalizeSeparators(p)
+	if strings.HasPrefix(p, "//?/") || strings.HasPrefix(p, "//./") {
+		p = p[4:]
+		if len(p) >= 4 && strings.EqualFold(p[:4], "UNC/") {
+			p = "//" + p[4:]
+		}
+	}
+	if d := driveLetter(p); d != "" {
+		return d, p[2:]
+	}
+	if strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "///") {
+		// //server/share[/rest]
+		parts := strings.SplitN(p[2:], "/", 3)
+		if len(parts) >= 2 && parts[0] != "" && parts[1] != "" {
+			volume = "//" + parts[0] + "/" + parts[1]
+			return volume, p[len(volume):]
+		}
+	}
+	return "", p
+}
+
+// RelFlavor returns a relative path from basepath to targpath using the
+// rules of flavor instead of the host's, without touching the filesystem.
+// Both '/' and '\' are accepted for FlavorWindows. Volumes and path
+// components are compared with the flavor's case folding (see
+// Flavor.KeyOptions), so `C:\a` and `c:\A\b` are on the same volume. The
+// result uses the separator of flavor.
+//
+// Paths on different drives or UNC shares return an error wrapping
+// ErrDifferentVolume. Like filepath.Rel, an error is also returned when one
+// path is absolute and the other is not, or when basepath contains ".."
+// elements that cannot be resolved lexically.
+func RelFlavor(basepath, targpath string, flavor Flavor) (string, error) {
+	keys := flavor.KeyOptions()
+	bv, brest := SplitVolumeFlavor(basepath, flavor)
+	tv, trest := SplitVolumeFlavor(targpath, flavor)
+	if !keys.Equal(bv, tv) {
+		return "", fmt.Errorf("Rel: %q and %q: %w", basepath, targpath, ErrDifferentVolume)
+	}
+
+	base, targ := path.Clean(brest), path.Clean(trest)
+	if brest == "" {
+		base = "."
+	}
+	if trest == "" {
+		targ = "."
+	}
+	if keys.Equal(base, targ) {
+		return ".", nil
+	}
+	if path.IsAbs(base) != path.IsAbs(targ) {
+		return "", errors.New("Rel: can't make " + targpath + " relative to " + basepath)
+	}
+
+	bparts, tparts := relParts(base), relParts(targ)
+	i := 0
+	for i < len(bparts) && i < len(tparts) && keys.Equal(bparts[i], tparts[i]) {
+		i++
+	}
+	up := bparts[i:]
+	for _, p := range up {
+		if p == ".." {
+			return "", errors.New("Rel: can't make " + targpath + " relative to " + basepath)
+		}
+	}
+	rel := make([]string, 0, len(up)+len(tparts)-i)
+	for range up {
+		rel = append(rel, "..")
+	}
+	rel = append(rel, tparts[i:]...)
+	if len(rel) == 0 {
+		return ".", nil
+	}
+	return strings.Join(rel, string(flavor.Separator())), nil
+}
+
+// relParts splits a cleaned slash path into its elements; "/" and "."
+// have none.
+func relParts(p string) []string {
+	p = strings.TrimPrefix(p, "/")
+	if p == "" || p == "." {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
@@ -0,0 +1,161 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ErrProtectedPath is returned by SafeRemoveAll for a path it refuses to
+// remove.
+var ErrProtectedPath = errors.New("refusing to remove protected path")
+
+// RemoveOptions controls SafeRemoveAll.
+type RemoveOptions struct {
+	// AllowedBase, if set, is the directory everything removed must be
+	// strictly inside of. Symlinks are resolved before the check.
+	AllowedBase string
+
+	// DryRun removes nothing and lists what would be removed.
+	DryRun bool
+
+	// Trash moves path to the user's trash following the freedesktop.org
+	// Trash specification instead of deleting it, so it can be restored
+	// from file managers. It is not supported on Windows and macOS.
+	Trash bool
+}
+
+// RemoveResult describes what SafeRemoveAll did or would do.
+type RemoveResult struct {
+	// Paths lists, with DryRun, path and every entry below it that would
+	// be removed, parents first. Symlinks are listed, not followed.
+	Paths []string
+
+	// TrashPath is where path was moved to with Trash.
+	TrashPath string
+}
+
+// SafeRemoveAll is os.RemoveAll with guard rails against removing the
+// wrong tree. It refuses, with an error wrapping ErrProtectedPath:
+//
+//   - an empty path, usually the sign of an unset variable;
+//   - filesystem and drive roots, the drives and mount points returned by
+//     GetDrives and, on Linux, the mount points of pseudo filesystems it
+//     leaves out (the tmpfs on /tmp, /run and /dev/shm, /proc, ...), and
+//     any directory containing one of them;
+//   - the home directory and its parents;
+//   - with opts.AllowedBase, anything not strictly inside of it.
+//
+// The checks are made on the absolute path and again with the symlinks in
+// its parent directories resolved. Like os.RemoveAll, a missing path is
+// not an error.
+func SafeRemoveAll(path string, opts RemoveOptions) (RemoveResult, error) {
+	var res RemoveResult
+	if path == "" {
+		return res, &fs.PathError{Op: "remove", Path: path, Err: fmt.Errorf("%w: empty path", ErrProtectedPath)}
+	}
+	abs, err := filepath.Abs(FromSlash(path))
+	if err != nil {
+		return res, err
+	}
+	candidates := []string{abs}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		if real := filepath.Join(dir, filepath.Base(abs)); real != abs {
+			candidates = append(candidates, real)
+		}
+	}
+	for _, p := range candidates {
+		if err := checkRemovable(p, opts.AllowedBase); err != nil {
+			return res, &fs.PathError{Op: "remove", Path: path, Err: err}
+		}
+	}
+
+	if _, err := os.Lstat(abs); isNotExist(err) {
+		return res, nil
+	} else if err != nil {
+		return res, err
+	}
+	switch {
+	case opts.DryRun:
+		err = filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+			if err != nil && p == abs {
+				return err
+			}
+			res.Paths = append(res.Paths, p)
+			return nil
+		})
+		return res, err
+	case opts.Trash:
+		res.TrashPath, err = moveToTrash(abs)
+		return res, err
+	}
+	return res, os.RemoveAll(abs)
+}
+
+// checkRemovable applies the guards of SafeRemoveAll to the clean
+// absolute path p.
+func checkRemovable(p, base string) error {
+	if filepath.Dir(p) == p {
+		return fmt.Errorf("%w: %s is a filesystem root", ErrProtectedPath, p)
+	}
+	for _, d := range protectedMounts() {
+		if contains(p, filepath.Clean(d)) {
+			return fmt.Errorf("%w: %s contains the drive or mount point %s", ErrProtectedPath, p, d)
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		for _, h := range resolvedForms(home) {
+			if contains(p, h) {
+				return fmt.Errorf("%w: %s contains the home directory", ErrProtectedPath, p)
+			}
+		}
+	}
+	if base != "" {
+		inside := false
+		for _, b := range resolvedForms(base) {
+			if p != b && contains(b, p) {
+				inside = true
+			}
+		}
+		if !inside {
+			return fmt.Errorf("%w: %s is not inside %s", ErrProtectedPath, p, base)
+		}
+	}
+	return nil
+}
+
+// protectedMounts returns the drives of GetDrives as paths, and on Linux
+// every mount point, pseudo filesystems included.
+func protectedMounts() []string {
+	drives, _ := GetDrives()
+	if runtime.GOOS == "windows" {
+		for i, d := range drives {
+			if len(d) == 1 {
+				drives[i] = d + `:\` // drive letter
+			}
+		}
+	}
+	if mounts, err := Mounts(); err == nil {
+		for _, m := range mounts {
+			drives = append(drives, m.MountPoint)
+		}
+	}
+	return drives
+}
+
+// resolvedForms returns the absolute form of p and, if it differs, the
+// form with symlinks resolved.
+func resolvedForms(p string) []string {
+	abs, err := filepath.Abs(FromSlash(p))
+	if err != nil {
+		return nil
+	}
+	forms := []string{abs}
+	if real, err := filepath.EvalSymlinks(abs); err == nil && real != abs {
+		forms = append(forms, real)
+	}
+	return forms
+}
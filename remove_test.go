@@ -0,0 +1,141 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSafeRemoveAllGuards(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	base := filepath.Join(home, "work")
+	writeTree(t, base, map[string]string{"keep": "x"})
+	outside := t.TempDir()
+	os.Symlink(outside, filepath.Join(base, "escape"))
+	writeTree(t, outside, map[string]string{"victim/f": "x"})
+
+	tests := []struct {
+		path string
+		base string
+	}{
+		{"", ""},
+		{"/", ""},
+		{home, ""},
+		{filepath.Dir(home), ""},
+		{base, base},
+		{filepath.Join(base, "..", "other"), base},
+		{outside, base},
+		// A symlinked parent pointing outside the base.
+		{filepath.Join(base, "escape", "victim"), base},
+	}
+	for _, tt := range tests {
+		res, err := SafeRemoveAll(tt.path, RemoveOptions{AllowedBase: tt.base})
+		if !errors.Is(err, ErrProtectedPath) {
+			t.Errorf("SafeRemoveAll(%q, base %q) = %+v, %v; want ErrProtectedPath", tt.path, tt.base, res, err)
+		}
+	}
+	if ok, _ := Exists(filepath.Join(outside, "victim", "f")); !ok {
+		t.Error("victim was removed")
+	}
+}
+
+func TestSafeRemoveAllProtectsPseudoMounts(t *testing.T) {
+	mounts, err := Mounts()
+	if err != nil {
+		t.Skip("no mount table:", err)
+	}
+	for _, m := range mounts {
+		if !m.Pseudo || m.MountPoint == "/" {
+			continue
+		}
+		if _, err := SafeRemoveAll(m.MountPoint, RemoveOptions{DryRun: true}); !errors.Is(err, ErrProtectedPath) {
+			t.Errorf("SafeRemoveAll(%q) on a %s mount: err = %v, want ErrProtectedPath", m.MountPoint, m.FSType, err)
+		}
+		return
+	}
+	t.Skip("no pseudo filesystem mounted")
+}
+
+func TestPathIsChildOf(t *testing.T) {
+	tests := []struct {
+		path, parent string
+		want         bool
+	}{
+		{"/a/b/c", "/a/b", true},
+		{"/a/bc", "/a/b", false},
+		{"/a/b", "/a/b", false},
+		{"/a", "/a/b", false},
+	}
+	for _, tt := range tests {
+		if got, err := PathIsChildOf(tt.path, tt.parent); err != nil || got != tt.want {
+			t.Errorf("PathIsChildOf(%q, %q) = %v, %v; want %v", tt.path, tt.parent, got, err, tt.want)
+		}
+	}
+}
+
+func TestSafeRemoveAll(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	writeTree(t, base, map[string]string{"tree/a": "a", "tree/sub/b": "b"})
+	tree := filepath.Join(base, "tree")
+
+	res, err := SafeRemoveAll(tree, RemoveOptions{AllowedBase: base, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{tree, filepath.Join(tree, "a"), filepath.Join(tree, "sub"), filepath.Join(tree, "sub", "b")}
+	if !reflect.DeepEqual(res.Paths, want) {
+		t.Errorf("dry run Paths = %v, want %v", res.Paths, want)
+	}
+	if ok, _ := Exists(tree); !ok {
+		t.Fatal("dry run removed the tree")
+	}
+
+	if _, err := SafeRemoveAll(tree, RemoveOptions{AllowedBase: base}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := LExists(tree); ok {
+		t.Error("tree still exists")
+	}
+	if _, err := SafeRemoveAll(tree, RemoveOptions{AllowedBase: base}); err != nil {
+		t.Errorf("removing a missing path: %v", err)
+	}
+}
+
+func TestSafeRemoveAllTrash(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("the freedesktop trash is not used on macOS")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	dir := filepath.Join(home, "docs")
+	writeTree(t, dir, map[string]string{"my file.txt": "x"})
+
+	var trashed []string
+	for i := 0; i < 2; i++ {
+		writeTree(t, dir, map[string]string{"my file.txt": "x"})
+		res, err := SafeRemoveAll(filepath.Join(dir, "my file.txt"), RemoveOptions{Trash: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		trashed = append(trashed, res.TrashPath)
+	}
+	trash := filepath.Join(home, ".local", "share", "Trash")
+	if want := []string{filepath.Join(trash, "files", "my file.txt"), filepath.Join(trash, "files", "my file.2.txt")}; !reflect.DeepEqual(trashed, want) {
+		t.Errorf("TrashPath = %v, want %v", trashed, want)
+	}
+	info := readFile(t, filepath.Join(trash, "info", "my file.txt.trashinfo"))
+	wantPath := "Path=" + strings.ReplaceAll(filepath.ToSlash(filepath.Join(dir, "my file.txt")), " ", "%20") + "\n"
+	if !strings.HasPrefix(info, "[Trash Info]\n") || !strings.Contains(info, wantPath) || !strings.Contains(info, "DeletionDate=") {
+		t.Errorf("trashinfo = %q", info)
+	}
+}
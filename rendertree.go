@@ -0,0 +1,240 @@
+package gofilepath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TreeFormat selects the output of RenderTree.
+type TreeFormat int
+
+const (
+	TreeUnicode TreeFormat = iota // ├── └── │ box drawing
+	TreeASCII                     // |-- `-- |
+	TreeJSON                      // the TreeNode hierarchy as indented JSON
+)
+
+// TreeOptions controls BuildTree and RenderTree.
+type TreeOptions struct {
+	// FS, if set, is the filesystem to list, and root is a slash-separated
+	// path within it ("." for its top). Otherwise root is a path on the OS
+	// filesystem.
+	FS fs.FS
+
+	// MaxDepth limits the levels shown like the maxdeep argument of the
+	// finders: 0 shows only the entries directly in root. Negative means
+	// unlimited.
+	MaxDepth int
+
+	// Filter selects the entries by their path relative to root. Include
+	// applies to files only; directories are shown unless they match
+	// Exclude.
+	Filter Filter
+
+	// DirsFirst lists directories before files; otherwise entries are
+	// sorted by name only.
+	DirsFirst bool
+
+	ShowSize bool // annotate entries with their size in bytes
+	ShowMode bool // annotate entries with their mode, like ls -l
+
+	Format TreeFormat
+}
+
+// TreeNode is an entry of a tree built by BuildTree.
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Kind     string      `json:"type"` // a PathKind name: file, dir, symlink, ...
+	Size     int64       `json:"size,omitempty"`
+	Mode     string      `json:"mode,omitempty"`
+	Target   string      `json:"target,omitempty"` // symlink target
+	Error    string      `json:"error,omitempty"`  // why a directory could not be read
+	Children []*TreeNode `json:"contents,omitempty"`
+
+	info fs.FileInfo
+}
+
+// BuildTree walks root and returns its entries as a tree, without
+// following symlinks. Symlink targets are read with fs.ReadLink, so they
+// are left empty when opts.FS is not an fs.ReadLinkFS. Unreadable
+// directories are kept with their Error set.
+func BuildTree(root string, opts TreeOptions) (*TreeNode, error) {
+	fsys := opts.FS
+	top := path.Clean(filepath.ToSlash(root))
+	if fsys == nil {
+		fsys, top = os.DirFS(FromSlash(root)), "."
+	}
+	info, err := fs.Stat(fsys, top)
+	if err != nil {
+		return nil, err
+	}
+
+	rootNode := &TreeNode{Name: root, Kind: KindOf(info.Mode()).String(), info: info}
+	nodes := map[string]*TreeNode{top: rootNode}
+	err = fs.WalkDir(fsys, top, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if n := nodes[p]; n != nil {
+				n.Error = err.Error() // the directory was listed but cannot be read
+			}
+			return nil
+		}
+		if p == top {
+			return nil
+		}
+		rel := strings.TrimPrefix(p, top+"/")
+		if top == "." {
+			rel = p
+		}
+		depth := strings.Count(rel, "/")
+		if opts.MaxDepth >= 0 && depth > opts.MaxDepth {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && opts.Filter.Excluded(FromSlash(rel)) || !d.IsDir() && !opts.Filter.Match(FromSlash(rel)) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // removed while walking
+		}
+		n := &TreeNode{Name: d.Name(), Kind: KindOf(info.Mode()).String(), info: info}
+		if info.Mode().IsRegular() {
+			n.Size = info.Size()
+		}
+		if opts.ShowMode {
+			n.Mode = info.Mode().String()
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			n.Target, _ = fs.ReadLink(fsys, p)
+		}
+		parent := nodes[path.Dir(p)]
+		parent.Children = append(parent.Children, n)
+		if d.IsDir() {
+			nodes[p] = n
+			if depth == opts.MaxDepth {
+				return fs.SkipDir // its children would not be shown
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.ShowMode {
+		rootNode.Mode = info.Mode().String()
+	}
+	if opts.DirsFirst {
+		sortDirsFirst(rootNode)
+	}
+	return rootNode, nil
+}
+
+func sortDirsFirst(n *TreeNode) {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		return n.Children[i].info.IsDir() && !n.Children[j].info.IsDir()
+	})
+	for _, c := range n.Children {
+		sortDirsFirst(c)
+	}
+}
+
+// RenderTree writes the tree of root to w like the tree command:
+//
+//	project
+//	├── cmd
+//	│   └── main.go
+//	├── go.mod
+//	└── latest -> cmd
+//
+//	1 directory, 2 files
+//
+// or, with TreeJSON, as the JSON encoding of the TreeNode hierarchy.
+func RenderTree(w io.Writer, root string, opts TreeOptions) error {
+	tree, err := BuildTree(root, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Format == TreeJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tree)
+	}
+
+	branch, last, pipe, space := "├── ", "└── ", "│   ", "    "
+	if opts.Format == TreeASCII {
+		branch, last, pipe = "|-- ", "`-- ", "|   "
+	}
+	var dirs, files int
+	var werr error
+	writeLine := func(s string) {
+		if werr == nil {
+			_, werr = io.WriteString(w, s+"\n")
+		}
+	}
+	var render func(n *TreeNode, prefix string)
+	render = func(n *TreeNode, prefix string) {
+		for i, c := range n.Children {
+			connector, indent := branch, pipe
+			if i == len(n.Children)-1 {
+				connector, indent = last, space
+			}
+			writeLine(prefix + connector + treeLabel(c, opts))
+			if c.info.IsDir() {
+				dirs++
+				render(c, prefix+indent)
+			} else {
+				files++
+			}
+		}
+	}
+	writeLine(tree.Name)
+	render(tree, "")
+	if werr != nil {
+		return werr
+	}
+	_, err = fmt.Fprintf(w, "\n%d %s, %d %s\n", dirs, plural(dirs, "directory", "directories"), files, plural(files, "file", "files"))
+	return err
+}
+
+// treeLabel formats the line of n: annotations, name, symlink target and
+// read error.
+func treeLabel(n *TreeNode, opts TreeOptions) string {
+	var sb strings.Builder
+	if opts.ShowMode || opts.ShowSize {
+		var ann []string
+		if opts.ShowMode {
+			ann = append(ann, n.info.Mode().String())
+		}
+		if opts.ShowSize {
+			ann = append(ann, fmt.Sprintf("%11d", n.info.Size()))
+		}
+		sb.WriteString("[" + strings.Join(ann, " ") + "]  ")
+	}
+	sb.WriteString(n.Name)
+	if n.Target != "" {
+		sb.WriteString(" -> " + n.Target)
+	}
+	if n.Error != "" {
+		sb.WriteString("  [error opening dir]")
+	}
+	return sb.String()
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,113 @@
+package gofilepath
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenderTreeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proj/go.mod":          {Data: []byte("module x\n")},
+		"proj/cmd/main.go":     {Data: []byte("package main\n")},
+		"proj/cmd/app/app.go":  {Data: []byte("package app\n")},
+		"proj/zdir/notes.txt":  {Data: []byte("notes")},
+		"proj/vendor/mod/a.go": {Data: []byte("package mod\n")},
+	}
+	tests := []struct {
+		opts TreeOptions
+		want string
+	}{
+		{TreeOptions{MaxDepth: -1, DirsFirst: true, Filter: Filter{Exclude: []string{"vendor"}}}, `proj
+├── cmd
+│   ├── app
+│   │   └── app.go
+│   └── main.go
+├── zdir
+│   └── notes.txt
+└── go.mod
+
+3 directories, 4 files
+`},
+		{TreeOptions{MaxDepth: 0, Format: TreeASCII}, "proj\n|-- cmd\n|-- go.mod\n|-- vendor\n`-- zdir\n\n3 directories, 1 file\n"},
+		{TreeOptions{MaxDepth: 1, ShowSize: true, Filter: Filter{Include: []string{"*.mod"}, Exclude: []string{"cmd", "vendor"}}}, `proj
+├── [          9]  go.mod
+└── [          0]  zdir
+
+1 directory, 1 file
+`},
+	}
+	for _, tt := range tests {
+		tt.opts.FS = fsys
+		var sb strings.Builder
+		if err := RenderTree(&sb, "proj", tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("RenderTree(%+v) =\n%s\nwant\n%s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+// unreadableFS fails to list the directory bad.
+type unreadableFS struct {
+	fstest.MapFS
+	bad string
+}
+
+func (f unreadableFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == f.bad {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestRenderTreeDoesNotReadBelowMaxDepth(t *testing.T) {
+	fsys := unreadableFS{fstest.MapFS{"top/locked/secret": {}, "top/open/f": {}}, "top/locked"}
+	var sb strings.Builder
+	if err := RenderTree(&sb, "top", TreeOptions{FS: fsys, Format: TreeASCII}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "top\n|-- locked\n`-- open\n\n2 directories, 0 files\n"; sb.String() != want {
+		t.Errorf("MaxDepth 0:\n%s\nwant\n%s", sb.String(), want)
+	}
+	sb.Reset()
+	if err := RenderTree(&sb, "top", TreeOptions{FS: fsys, MaxDepth: 1, Format: TreeASCII}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "locked  [error opening dir]") {
+		t.Errorf("MaxDepth 1 does not report the unreadable directory:\n%s", sb.String())
+	}
+}
+
+func TestRenderTreeJSON(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a/b.txt": "bb"})
+	if runtime.GOOS != "windows" {
+		os.Symlink("a/b.txt", filepath.Join(root, "link"))
+	}
+	var sb strings.Builder
+	if err := RenderTree(&sb, root, TreeOptions{MaxDepth: -1, Format: TreeJSON}); err != nil {
+		t.Fatal(err)
+	}
+	var tree TreeNode
+	if err := json.Unmarshal([]byte(sb.String()), &tree); err != nil {
+		t.Fatal(err)
+	}
+	if tree.Kind != "dir" || len(tree.Children) == 0 || tree.Children[0].Name != "a" {
+		t.Fatalf("tree = %s", sb.String())
+	}
+	if b := tree.Children[0].Children[0]; b.Name != "b.txt" || b.Size != 2 || b.Kind != "file" {
+		t.Errorf("a/b.txt = %+v", b)
+	}
+	if runtime.GOOS != "windows" {
+		if l := tree.Children[1]; l.Kind != "symlink" || l.Target != "a/b.txt" {
+			t.Errorf("link = %+v", l)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gofilepath
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// AmbiguousPathError is returned by ResolveCaseInsensitive when a path
+// component matches several entries that differ only in case.
+type AmbiguousPathError struct {
+	Path       string   // directory holding the candidates
+	Name       string   // requested component
+	Candidates []string // matching entry names
+}
+
+func (e *AmbiguousPathError) Error() string {
+	return fmt.Sprintf("ambiguous path component %q in %s: matches %s", e.Name, e.Path, strings.Join(e.Candidates, ", "))
+}
+
+// caseFoldKey compares names case-insensitively and ignoring the Unicode
+// normalization form, as Windows and macOS clients expect.
+var caseFoldKey = PathKeyOptions{Flavor: FlavorPOSIX, FoldCase: true, Normalize: true}
+
+// CaseResolver maps case-insensitive relative paths to the entries that
+// exist on a case-sensitive filesystem. It caches directory listings, so
+// repeated lookups below the same directories are cheap. It is safe for
+// concurrent use.
+type CaseResolver struct {
+	mu   sync.Mutex
+	dirs map[string]map[string][]string // dir -> folded name -> names
+}
+
+// NewCaseResolver returns a CaseResolver with an empty cache.
+func NewCaseResolver() *CaseResolver {
+	return &CaseResolver{dirs: map[string]map[string][]string{}}
+}
+
+// Reset drops all cached directory listings.
+func (r *CaseResolver) Reset() {
+	r.mu.Lock()
+	r.dirs = map[string]map[string][]string{}
+	r.mu.Unlock()
+}
+
+// Invalidate drops the cached listing of dir.
+func (r *CaseResolver) Invalidate(dir string) {
+	r.mu.Lock()
+	delete(r.dirs, filepath.Clean(dir))
+	r.mu.Unlock()
+}
+
+// listing returns the folded listing of dir, reading it if it is not cached
+// or if reload is set.
+func (r *CaseResolver) listing(dir string, reload bool) (map[string][]string, error) {
+	r.mu.Lock()
+	l, ok := r.dirs[dir]
+	r.mu.Unlock()
+	if ok && !reload {
+		return l, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	l = make(map[string][]string, len(entries))
+	for _, e := range entries {
+		k := caseFoldKey.Key(e.Name())
+		l[k] = append(l[k], e.Name())
+	}
+	r.mu.Lock()
+	r.dirs[dir] = l
+	r.mu.Unlock()
+	return l, nil
+}
+
+// Resolve returns root joined with the on-disk spelling of rel.
+// rel may use '/' or '\' separators and must stay inside root. Each
+// component is matched exactly if possible, otherwise case-insensitively;
+// a component matching several entries fails with *AmbiguousPathError and
+// a missing one with an error wrapping fs.ErrNotExist.
+func (r *CaseResolver) Resolve(root, rel string) (string, error) {
+	rel = CleanSmart(rel)
+	if rel == "." {
+		return filepath.Clean(root), nil
+	}
+	if IsAbsSmart(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("path %q is not inside root", rel)
+	}
+
+	cur := filepath.Clean(root)
+	for _, name := range strings.Split(rel, "/") {
+		if _, err := os.Lstat(filepath.Join(cur, name)); err == nil {
+			cur = filepath.Join(cur, name)
+			continue
+		}
+		key := caseFoldKey.Key(name)
+		l, err := r.listing(cur, false)
+		if err == nil && len(l[key]) == 0 {
+			// The directory may have changed since it was cached.
+			l, err = r.listing(cur, true)
+		}
+		if err != nil {
+			return "", err
+		}
+		switch matches := l[key]; len(matches) {
+		case 0:
+			return "", &fs.PathError{Op: "resolve", Path: filepath.Join(cur, name), Err: fs.ErrNotExist}
+		case 1:
+			cur = filepath.Join(cur, matches[0])
+		default:
+			return "", &AmbiguousPathError{Path: cur, Name: name, Candidates: append([]string{}, matches...)}
+		}
+	}
+	return cur, nil
+}
+
+// ResolveCaseInsensitive resolves rel below root like CaseResolver.Resolve,
+// without keeping a cache between calls.
+//
+//	ResolveCaseInsensitive("/srv", "Documents/Report.DOCX") → "/srv/documents/report.docx"
+func ResolveCaseInsensitive(root, rel string) (string, error) {
+	return NewCaseResolver().Resolve(root, rel)
+}
@@ -0,0 +1,48 @@
+package gofilepath
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveCaseInsensitive(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "documents"), 0o755)
+	os.WriteFile(filepath.Join(root, "documents", "report.docx"), nil, 0o644)
+
+	got, err := ResolveCaseInsensitive(root, `Documents\Report.DOCX`)
+	if want := filepath.Join(root, "documents", "report.docx"); err != nil || got != want {
+		t.Errorf("ResolveCaseInsensitive = %q, %v, want %q", got, err, want)
+	}
+	if _, err := ResolveCaseInsensitive(root, "documents/missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := ResolveCaseInsensitive(root, "../etc"); err == nil {
+		t.Errorf("path outside root should fail")
+	}
+
+	r := NewCaseResolver()
+	if _, err := r.Resolve(root, "DOCUMENTS/report.docx"); err != nil {
+		t.Fatal(err)
+	}
+	// A file created after the listing was cached is still found.
+	os.WriteFile(filepath.Join(root, "documents", "new.txt"), nil, 0o644)
+	if _, err := r.Resolve(root, "DOCUMENTS/NEW.TXT"); err != nil {
+		t.Errorf("Resolve after change: %v", err)
+	}
+
+	// Case-sensitive filesystems can hold names that differ only in case.
+	if err := os.WriteFile(filepath.Join(root, "documents", "Report.docx"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "documents", "REPORT.DOCX")); err == nil {
+		t.Skip("filesystem is case-insensitive")
+	}
+	var amb *AmbiguousPathError
+	if _, err := ResolveCaseInsensitive(root, "documents/REPORT.docx"); !errors.As(err, &amb) || len(amb.Candidates) != 2 {
+		t.Errorf("ambiguous match error = %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package gofilepath
+
+import (
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ShortenOptions controls Shorten.
+type ShortenOptions struct {
+	// Ellipsis replaces elided components. Empty means "…".
+	Ellipsis string
+
+	// ReplaceHome replaces the home directory with "~" first, see Contract.
+	// Expand supplies the home directory lookup.
+	ReplaceHome bool
+	Expand      ExpandOptions
+
+	// UniquePrefixes abbreviates directory components, like the fish shell,
+	// to the shortest prefix that is unique among the entries of their
+	// parent directory on disk (one character if the directory cannot be
+	// read). The last component is never abbreviated.
+	UniquePrefixes bool
+}
+
+// DisplayWidth returns the number of terminal cells needed to print s:
+// East Asian wide and fullwidth characters take two cells, combining marks
+// and other zero-width characters none.
+func DisplayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || !unicode.IsPrint(r) && r != ' ':
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Shorten abbreviates p to fit in maxWidth terminal cells for display.
+// The steps are applied in order until the path fits: replacing the home
+// directory with "~", abbreviating directories to unique prefixes, and
+// eliding middle components, keeping the first and as many trailing
+// components as fit:
+//
+//	Shorten("/home/u/src/project/internal/deep/file.go", 24, ShortenOptions{}) → "/home/u/…/deep/file.go"
+//
+// If even the last component does not fit, its end is kept. The result is
+// for display only and cannot be used to open the file.
+func Shorten(p string, maxWidth int, opts ShortenOptions) string {
+	ellipsis := opts.Ellipsis
+	if ellipsis == "" {
+		ellipsis = "…"
+	}
+	orig := p
+	if opts.ReplaceHome {
+		p = Contract(p, opts.Expand)
+	}
+	if DisplayWidth(p) <= maxWidth {
+		return p
+	}
+
+	sep := GetPathSeparator(p)
+	if sep == "" {
+		return truncateLeft(p, maxWidth, ellipsis)
+	}
+	comps := strings.Split(p, sep)
+	if opts.UniquePrefixes {
+		// Find the directory on disk that comps[0] stands for.
+		first := comps[0]
+		if first == "~" && p != orig {
+			if h, err := opts.Expand.homeDir(""); err == nil {
+				first = h
+			}
+		}
+		if first == "" {
+			first = sep
+		}
+		abbreviateComponents(comps, sep, first)
+		if s := strings.Join(comps, sep); DisplayWidth(s) <= maxWidth {
+			return s
+		}
+	}
+	if len(comps) <= 2 {
+		return truncateLeft(p, maxWidth, ellipsis)
+	}
+
+	// Keep comps[:head] and comps[tail:], eliding at least one component
+	// in between, and grow both ends alternately while the result fits.
+	build := func(head, tail int) string {
+		parts := append(append(append([]string{}, comps[:head]...), ellipsis), comps[tail:]...)
+		return strings.Join(parts, sep)
+	}
+	head, tail := 1, len(comps)-1
+	best := build(head, tail)
+	if DisplayWidth(best) > maxWidth {
+		return truncateLeft(comps[len(comps)-1], maxWidth, ellipsis)
+	}
+	growHead, growTail := true, true
+	for growHead || growTail {
+		if growTail {
+			if s := build(head, tail-1); tail-1 > head && DisplayWidth(s) <= maxWidth {
+				tail, best = tail-1, s
+			} else {
+				growTail = false
+			}
+		}
+		if growHead {
+			if s := build(head+1, tail); head+1 < tail && DisplayWidth(s) <= maxWidth {
+				head, best = head+1, s
+			} else {
+				growHead = false
+			}
+		}
+	}
+	return best
+}
+
+// truncateLeft keeps the end of s that fits in maxWidth cells, prefixed
+// with ellipsis.
+func truncateLeft(s string, maxWidth int, ellipsis string) string {
+	if DisplayWidth(s) <= maxWidth {
+		return s
+	}
+	budget := maxWidth - DisplayWidth(ellipsis)
+	if budget <= 0 {
+		return ellipsis
+	}
+	i, w := len(s), 0
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		if w+runeWidth(r) > budget {
+			break
+		}
+		w += runeWidth(r)
+		i -= size
+	}
+	return ellipsis + s[i:]
+}
+
+// abbreviateComponents replaces the directory components of comps with
+// their shortest unique prefix. first is the directory on disk that
+// comps[0] stands for.
+func abbreviateComponents(comps []string, sep, first string) {
+	dir := first
+	for i := 1; i < len(comps)-1; i++ {
+		name := comps[i]
+		if name == "" {
+			continue
+		}
+		comps[i] = uniquePrefix(dir, name)
+		dir = strings.TrimSuffix(dir, sep) + sep + name
+	}
+}
+
+// uniquePrefix returns the shortest prefix of name (at least one character,
+// two for dot files) that no other entry of dir starts with.
+func uniquePrefix(dir, name string) string {
+	min := 1
+	if strings.HasPrefix(name, ".") {
+		min = 2
+	}
+	runes := []rune(name)
+	if len(runes) <= min {
+		return name
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return string(runes[:min])
+	}
+	for n := min; n < len(runes); n++ {
+		prefix := string(runes[:n])
+		unique := true
+		for _, e := range entries {
+			if e.Name() != name && strings.HasPrefix(e.Name(), prefix) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return prefix
+		}
+	}
+	return name
+}
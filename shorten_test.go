@@ -0,0 +1,71 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"cafe\u0301", 4},
+		{"…", 1},
+	}
+	for _, tt := range tests {
+		if got := DisplayWidth(tt.s); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestShorten(t *testing.T) {
+	home := ExpandOptions{HomeDir: func(string) (string, error) { return "/home/u", nil }}
+	tests := []struct {
+		p     string
+		width int
+		opts  ShortenOptions
+		want  string
+	}{
+		{"/home/u/src/project/internal/deep/file.go", 100, ShortenOptions{}, "/home/u/src/project/internal/deep/file.go"},
+		{"/home/u/src/project/internal/deep/file.go", 24, ShortenOptions{}, "/home/u/…/deep/file.go"},
+		{"/home/u/src/project/internal/deep/file.go", 24, ShortenOptions{ReplaceHome: true, Expand: home}, "~/src/…/deep/file.go"},
+		{"/home/u/src/project/file.go", 12, ShortenOptions{}, "/…/file.go"},
+		{"/a/very-long-file-name.go", 10, ShortenOptions{}, "…e-name.go"},
+		{`C:\Users\me\docs\日本語\report.docx`, 24, ShortenOptions{}, `C:\…\日本語\report.docx`},
+		{"/srv/data-files", 8, ShortenOptions{Ellipsis: "..."}, "...files"},
+	}
+	for _, tt := range tests {
+		got := Shorten(tt.p, tt.width, tt.opts)
+		if got != tt.want {
+			t.Errorf("Shorten(%q, %d) = %q, want %q", tt.p, tt.width, got, tt.want)
+		}
+		if DisplayWidth(got) > tt.width {
+			t.Errorf("Shorten(%q, %d) = %q is too wide", tt.p, tt.width, got)
+		}
+	}
+}
+
+func TestShortenUniquePrefixes(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "projects", "foo"), 0o755)
+	os.MkdirAll(filepath.Join(root, "private"), 0o755)
+	os.MkdirAll(filepath.Join(root, ".config"), 0o755)
+	file := filepath.Join(root, "projects", "foo", "main.go")
+
+	opts := ShortenOptions{
+		UniquePrefixes: true,
+		ReplaceHome:    true,
+		Expand:         ExpandOptions{HomeDir: func(string) (string, error) { return root, nil }},
+	}
+	if got, want := Shorten(file, 15, opts), "~/pro/f/main.go"; got != want {
+		t.Errorf("Shorten unique prefixes = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+package gofilepath
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotOptions controls Snapshot.
+type SnapshotOptions struct {
+	// Filter selects the recorded entries by their path relative to root.
+	// Directories matching Filter.Exclude are not descended into.
+	Filter Filter
+
+	// MaxDepth limits the recorded entries like the maxdeep argument of the
+	// finders: 0 records only the entries directly in root. Negative means
+	// unlimited.
+	MaxDepth int
+
+	// Hash records the SHA-256 of regular files, so content changes that
+	// keep size and mtime are detected and renames can be matched where
+	// inode numbers are not available. It reads every file.
+	Hash bool
+}
+
+// SnapshotEntry is the recorded state of one entry.
+type SnapshotEntry struct {
+	Path    string      `json:"path"` // relative to the root, with '/' separators
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mtime"`
+	Hash    string      `json:"hash,omitempty"` // hex SHA-256 if SnapshotOptions.Hash
+	Link    string      `json:"link,omitempty"` // symlink target
+	Dev     uint64      `json:"dev,omitempty"`  // device and inode, where available
+	Ino     uint64      `json:"ino,omitempty"`
+}
+
+// TreeSnapshot is the state of a tree at one point in time. It can be
+// stored with encoding/json and compared with a later one using Diff.
+type TreeSnapshot struct {
+	Root    string          `json:"root"`
+	Time    time.Time       `json:"time"`
+	Entries []SnapshotEntry `json:"entries"` // sorted by Path
+	Errors  int             `json:"errors,omitempty"`
+}
+
+// Snapshot records every entry below root. Symlinks are recorded, not
+// followed. Like DirSize, unreadable entries are skipped and counted in
+// Errors; only a failure to read root itself is returned as an error.
+func Snapshot(root string, opts SnapshotOptions) (*TreeSnapshot, error) {
+	root = FromSlash(root)
+	if _, err := os.ReadDir(root); err != nil {
+		return nil, err
+	}
+	snap := &TreeSnapshot{Root: root, Time: time.Now()}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !isNotExist(err) {
+				snap.Errors++
+			}
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		depth := strings.Count(rel, string(filepath.Separator))
+		if opts.MaxDepth >= 0 && depth > opts.MaxDepth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && opts.Filter.Excluded(rel) {
+			return filepath.SkipDir
+		}
+		if !opts.Filter.Match(rel) {
+			return nil
+		}
+		e, err := snapshotEntry(path, rel, d, opts.Hash)
+		if err != nil {
+			if !isNotExist(err) {
+				snap.Errors++
+			}
+			return nil
+		}
+		snap.Entries = append(snap.Entries, e)
+		return nil
+	})
+	sort.Slice(snap.Entries, func(i, j int) bool { return snap.Entries[i].Path < snap.Entries[j].Path })
+	return snap, nil
+}
+
+func snapshotEntry(path, rel string, d fs.DirEntry, hash bool) (SnapshotEntry, error) {
+	info, err := d.Info()
+	if err != nil {
+		return SnapshotEntry{}, err
+	}
+	e := SnapshotEntry{
+		Path:    filepath.ToSlash(rel),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+	}
+	if id, _, _, ok := fileIdentity(info); ok {
+		e.Dev, e.Ino = id.dev, id.ino
+	}
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		if e.Link, err = os.Readlink(path); err != nil {
+			return SnapshotEntry{}, err
+		}
+	case info.Mode().IsRegular() && hash:
+		if e.Hash, err = HashFile(path, SHA256); err != nil {
+			return SnapshotEntry{}, err
+		}
+	}
+	return e, nil
+}
+
+// Lookup returns the entry recorded for the slash-separated relative path.
+func (s *TreeSnapshot) Lookup(rel string) (SnapshotEntry, bool) {
+	i := sort.Search(len(s.Entries), func(i int) bool { return s.Entries[i].Path >= rel })
+	if i < len(s.Entries) && s.Entries[i].Path == rel {
+		return s.Entries[i], true
+	}
+	return SnapshotEntry{}, false
+}
+
+// RenamedEntry is an entry that moved between two snapshots.
+type RenamedEntry struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	New  SnapshotEntry `json:"new"`
+}
+
+// SnapshotDiff lists the differences between two snapshots. Each list is
+// sorted by path.
+type SnapshotDiff struct {
+	Added    []SnapshotEntry `json:"added,omitempty"`
+	Removed  []SnapshotEntry `json:"removed,omitempty"`
+	Modified []SnapshotEntry `json:"modified,omitempty"` // the new state
+	Renamed  []RenamedEntry  `json:"renamed,omitempty"`
+}
+
+// Empty reports whether the snapshots were equal.
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Modified)+len(d.Renamed) == 0
+}
+
+// Diff compares two snapshots of the same tree.
+//
+// An entry present under the same path in both is modified if its type,
+// permissions, symlink target or, for non-directories, size, mtime or hash
+// changed; hashes are only compared when both snapshots have them. A
+// directory whose only change is its mtime is not reported: the changes of
+// its children are.
+//
+// A removed and an added entry of the same type are reported as a rename
+// if they have the same device, inode, size and mtime, or, failing that,
+// the same size and content hash. A file that was moved and modified in
+// between is reported as removed and added.
+func Diff(old, new *TreeSnapshot) SnapshotDiff {
+	var d SnapshotDiff
+	var added, removed []SnapshotEntry
+	i, j := 0, 0
+	for i < len(old.Entries) || j < len(new.Entries) {
+		switch {
+		case j == len(new.Entries) || i < len(old.Entries) && old.Entries[i].Path < new.Entries[j].Path:
+			removed = append(removed, old.Entries[i])
+			i++
+		case i == len(old.Entries) || new.Entries[j].Path < old.Entries[i].Path:
+			added = append(added, new.Entries[j])
+			j++
+		default:
+			if entryModified(old.Entries[i], new.Entries[j]) {
+				d.Modified = append(d.Modified, new.Entries[j])
+			}
+			i++
+			j++
+		}
+	}
+
+	// Pair renames, preferring inode matches over hash matches.
+	matched := make([]bool, len(removed))
+	pair := func(key func(e SnapshotEntry) string) {
+		index := map[string][]int{}
+		for r, e := range removed {
+			if k := key(e); k != "" && !matched[r] {
+				index[k] = append(index[k], r)
+			}
+		}
+		rest := added[:0:0]
+		for _, a := range added {
+			k := key(a)
+			if k == "" || len(index[k]) == 0 {
+				rest = append(rest, a)
+				continue
+			}
+			r := index[k][0]
+			index[k] = index[k][1:]
+			matched[r] = true
+			d.Renamed = append(d.Renamed, RenamedEntry{From: removed[r].Path, To: a.Path, New: a})
+		}
+		added = rest
+	}
+	pair(func(e SnapshotEntry) string {
+		if e.Ino == 0 {
+			return ""
+		}
+		// Inodes are reused quickly, so a new file could take the number
+		// of a removed one; a renamed file keeps its size and mtime.
+		return fmt.Sprintf("%v %d:%d %d %d %s", e.Mode.Type(), e.Dev, e.Ino, e.Size, e.ModTime.UnixNano(), e.Hash)
+	})
+	pair(func(e SnapshotEntry) string {
+		if e.Hash == "" {
+			return ""
+		}
+		return fmt.Sprintf("%d:%s", e.Size, e.Hash)
+	})
+
+	d.Added = added
+	for r, e := range removed {
+		if !matched[r] {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+	sort.Slice(d.Renamed, func(i, j int) bool { return d.Renamed[i].To < d.Renamed[j].To })
+	return d
+}
+
+func entryModified(a, b SnapshotEntry) bool {
+	if a.Mode != b.Mode || a.Link != b.Link {
+		return true
+	}
+	if a.Mode.IsDir() {
+		return false
+	}
+	if a.Hash != "" && b.Hash != "" && a.Hash != b.Hash {
+		return true
+	}
+	return a.Size != b.Size || !a.ModTime.Equal(b.ModTime)
+}
@@ -0,0 +1,114 @@
+package gofilepath
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		p := filepath.Join(root, FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("keep.txt", "same")
+	write("edit.txt", "before")
+	write("gone.txt", "bye")
+	write("move.txt", "moving")
+	write("dir/deep/x.txt", "deep")
+	write("skip/y.txt", "skipped")
+
+	opts := SnapshotOptions{MaxDepth: -1, Hash: true, Filter: Filter{Exclude: []string{"skip"}}}
+	old, err := Snapshot(root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := old.Lookup("skip/y.txt"); ok {
+		t.Error("excluded directory was recorded")
+	}
+	if _, ok := old.Lookup("dir/deep/x.txt"); !ok {
+		t.Error("dir/deep/x.txt was not recorded")
+	}
+
+	// Persist and restore the snapshot, as between two runs.
+	data, err := json.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old = new(TreeSnapshot)
+	if err := json.Unmarshal(data, old); err != nil {
+		t.Fatal(err)
+	}
+
+	write("edit.txt", "after!")
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(root, "edit.txt"), later, later)
+	os.Remove(filepath.Join(root, "gone.txt"))
+	os.Rename(filepath.Join(root, "move.txt"), filepath.Join(root, "moved.txt"))
+	write("new.txt", "hello")
+
+	cur, err := Snapshot(root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Diff(old, cur)
+	paths := func(entries []SnapshotEntry) []string {
+		var ps []string
+		for _, e := range entries {
+			ps = append(ps, e.Path)
+		}
+		return ps
+	}
+	if got := paths(d.Added); !reflect.DeepEqual(got, []string{"new.txt"}) {
+		t.Errorf("Added = %v", got)
+	}
+	if got := paths(d.Removed); !reflect.DeepEqual(got, []string{"gone.txt"}) {
+		t.Errorf("Removed = %v", got)
+	}
+	if got := paths(d.Modified); !reflect.DeepEqual(got, []string{"edit.txt"}) {
+		t.Errorf("Modified = %v", got)
+	}
+	if len(d.Renamed) != 1 || d.Renamed[0].From != "move.txt" || d.Renamed[0].To != "moved.txt" {
+		t.Errorf("Renamed = %+v", d.Renamed)
+	}
+	if !Diff(cur, cur).Empty() {
+		t.Error("Diff of a snapshot with itself is not empty")
+	}
+}
+
+func TestDiffRenameByHash(t *testing.T) {
+	mtime := time.Unix(1700000000, 0)
+	old := &TreeSnapshot{Entries: []SnapshotEntry{{Path: "a", Size: 3, ModTime: mtime, Hash: "abc"}}}
+	cur := &TreeSnapshot{Entries: []SnapshotEntry{{Path: "b", Size: 3, ModTime: mtime, Hash: "abc"}}}
+	d := Diff(old, cur)
+	if len(d.Renamed) != 1 || len(d.Added)+len(d.Removed) != 0 {
+		t.Errorf("Diff = %+v, want one rename", d)
+	}
+}
+
+func TestSnapshotMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "a", "b"), 0o755)
+	os.WriteFile(filepath.Join(root, "a", "b", "c"), nil, 0o644)
+	snap, err := Snapshot(root, SnapshotOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range snap.Entries {
+		got = append(got, e.Path)
+	}
+	if want := []string{"a", "a/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,310 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SyncOptions controls Sync.
+type SyncOptions struct {
+	// Checksum compares files by SHA-256 instead of size and mtime. Both
+	// trees are read completely.
+	Checksum bool
+
+	// ModifyWindow is the largest mtime difference still considered equal,
+	// for destinations with a coarser timestamp resolution (FAT uses two
+	// seconds). Zero compares exactly.
+	ModifyWindow time.Duration
+
+	// Delete removes destination entries that do not exist in src.
+	// Entries excluded by Filter are never deleted.
+	Delete bool
+
+	// Filter selects the synchronized entries by their path relative to
+	// the roots. Include applies to files only; directories are
+	// synchronized unless they match Exclude.
+	Filter Filter
+
+	// DryRun only computes the plan.
+	DryRun bool
+
+	// Progress, if not nil, is called while files are copied.
+	Progress func(CopyProgress)
+}
+
+// SyncAction is a step of a SyncPlan.
+type SyncAction int
+
+const (
+	SyncDelete SyncAction = iota // remove the destination entry
+	SyncMkdir                    // create a missing directory
+	SyncCopy                     // copy an entry missing in the destination
+	SyncUpdate                   // replace a changed entry, or set a directory's mode and mtime
+)
+
+func (a SyncAction) String() string {
+	switch a {
+	case SyncDelete:
+		return "delete"
+	case SyncMkdir:
+		return "mkdir"
+	case SyncCopy:
+		return "copy"
+	case SyncUpdate:
+		return "update"
+	}
+	return fmt.Sprintf("SyncAction(%d)", int(a))
+}
+
+// SyncOp is one step of a SyncPlan.
+type SyncOp struct {
+	Action SyncAction
+	Path   string // relative to the roots, with '/' separators
+	Size   int64  // bytes copied by SyncCopy and SyncUpdate
+}
+
+func (op SyncOp) String() string {
+	return op.Action.String() + " " + op.Path
+}
+
+// SyncPlan is the list of steps that make dst mirror src, in execution
+// order: deletions, then directories, then files. The mode and mtime of
+// directories are applied last, after their content is written.
+type SyncPlan struct {
+	Ops   []SyncOp
+	Bytes int64 // total bytes to copy
+}
+
+// String returns the plan with one step per line.
+func (p *SyncPlan) String() string {
+	var sb strings.Builder
+	for _, op := range p.Ops {
+		sb.WriteString(op.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Sync makes dst a mirror of src, like rsync -a: it copies new and changed
+// files with their mode and mtime, recreates symlinks, creates missing
+// directories, updates the mode and mtime of existing ones and, with
+// opts.Delete, removes extraneous destination entries. Unchanged files
+// are not touched. dst is created if needed.
+//
+// Sync returns the plan it executed, or with opts.DryRun the plan it
+// would execute. Like rsync, it deletes nothing if some source entries
+// could not be read, and reports that as an error after the copy.
+func Sync(src, dst string, opts SyncOptions) (*SyncPlan, error) {
+	src, dst = FromSlash(src), FromSlash(dst)
+	absSrc, err1 := filepath.Abs(src)
+	absDst, err2 := filepath.Abs(dst)
+	if err1 == nil && err2 == nil && contains(absSrc, absDst) {
+		return nil, &fs.PathError{Op: "sync", Path: dst, Err: errors.New("destination is inside the source")}
+	}
+
+	// Snapshot applies Include to directories too: leave it out and drop
+	// the files it does not match afterwards.
+	snapOpts := SnapshotOptions{
+		Filter:   Filter{Exclude: opts.Filter.Exclude, MatchFunc: opts.Filter.MatchFunc},
+		MaxDepth: -1,
+		Hash:     opts.Checksum,
+	}
+	from, err := Snapshot(src, snapOpts)
+	if err != nil {
+		return nil, err
+	}
+	to := &TreeSnapshot{Root: dst}
+	if ok, err := Exists(dst); err != nil {
+		return nil, err
+	} else if ok {
+		if to, err = Snapshot(dst, snapOpts); err != nil {
+			return nil, err
+		}
+	}
+	for _, snap := range []*TreeSnapshot{from, to} {
+		snap.Entries = syncIncluded(snap.Entries, opts.Filter)
+	}
+
+	plan := planSync(from, to, opts, from.Errors == 0)
+	if opts.DryRun {
+		return plan, nil
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return plan, err
+	}
+	if err := executeSync(plan, src, dst, opts); err != nil {
+		return plan, err
+	}
+	if from.Errors > 0 {
+		return plan, fmt.Errorf("sync %s: %d source entries could not be read, nothing was deleted", src, from.Errors)
+	}
+	return plan, nil
+}
+
+// syncIncluded keeps the directories and the other entries that match
+// the Include patterns of f.
+func syncIncluded(entries []SnapshotEntry, f Filter) []SnapshotEntry {
+	if len(f.Include) == 0 {
+		return entries
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Mode.IsDir() || f.Match(filepath.FromSlash(e.Path)) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// planSync compares the snapshots of src and dst.
+func planSync(from, to *TreeSnapshot, opts SyncOptions, allowDelete bool) *SyncPlan {
+	var deletes, mkdirs, copies []SyncOp
+	deleted := "" // last deleted directory, whose children need no steps
+	remove := func(e SnapshotEntry) {
+		if deleted != "" && strings.HasPrefix(e.Path, deleted+"/") {
+			return
+		}
+		deletes = append(deletes, SyncOp{Action: SyncDelete, Path: e.Path})
+		if e.Mode.IsDir() {
+			deleted = e.Path
+		}
+	}
+	add := func(e SnapshotEntry, action SyncAction) {
+		if e.Mode.IsDir() {
+			if action == SyncCopy {
+				action = SyncMkdir
+			}
+			mkdirs = append(mkdirs, SyncOp{Action: action, Path: e.Path})
+			return
+		}
+		op := SyncOp{Action: action, Path: e.Path}
+		if e.Mode.IsRegular() {
+			op.Size = e.Size
+		}
+		copies = append(copies, op)
+	}
+
+	i, j := 0, 0
+	for i < len(from.Entries) || j < len(to.Entries) {
+		switch {
+		case j == len(to.Entries) || i < len(from.Entries) && from.Entries[i].Path < to.Entries[j].Path:
+			add(from.Entries[i], SyncCopy)
+			i++
+		case i == len(from.Entries) || to.Entries[j].Path < from.Entries[i].Path:
+			if opts.Delete && allowDelete {
+				remove(to.Entries[j])
+			}
+			j++
+		default:
+			s, d := from.Entries[i], to.Entries[j]
+			switch {
+			case s.Mode.Type() != d.Mode.Type():
+				// The destination entry of the wrong type must go even
+				// without Delete.
+				remove(d)
+				add(s, SyncCopy)
+			case syncChanged(s, d, opts):
+				add(s, SyncUpdate)
+			}
+			i++
+			j++
+		}
+	}
+
+	plan := &SyncPlan{}
+	plan.Ops = append(plan.Ops, deletes...)
+	plan.Ops = append(plan.Ops, mkdirs...)
+	plan.Ops = append(plan.Ops, copies...)
+	for _, op := range copies {
+		plan.Bytes += op.Size
+	}
+	return plan
+}
+
+func syncChanged(s, d SnapshotEntry, opts SyncOptions) bool {
+	if s.Mode.Type()&fs.ModeSymlink != 0 {
+		return s.Link != d.Link
+	}
+	if s.Mode.Perm() != d.Mode.Perm() || !s.Mode.IsDir() && s.Size != d.Size {
+		return true
+	}
+	if opts.Checksum && !s.Mode.IsDir() {
+		return s.Hash != d.Hash
+	}
+	diff := s.ModTime.Sub(d.ModTime)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > opts.ModifyWindow
+}
+
+func executeSync(plan *SyncPlan, src, dst string, opts SyncOptions) error {
+	copyOpts := CopyOptions{
+		Conflict: ConflictOverwrite,
+		Preserve: PreserveMode | PreserveTimes,
+		Progress: opts.Progress,
+	}
+	// Directory modes and times are applied after their content is
+	// written, deepest first: those of the planned directories and those
+	// of the parents of every changed entry, whose mtime the change bumped.
+	dirs := map[string]bool{}
+	for _, op := range plan.Ops {
+		from := filepath.Join(src, filepath.FromSlash(op.Path))
+		to := filepath.Join(dst, filepath.FromSlash(op.Path))
+		for p := path.Dir(op.Path); ; p = path.Dir(p) {
+			dirs[p] = true
+			if p == "." {
+				break
+			}
+		}
+		var err error
+		switch op.Action {
+		case SyncDelete:
+			err = os.RemoveAll(to)
+		case SyncMkdir:
+			if err = os.MkdirAll(to, 0o700); err == nil {
+				dirs[op.Path] = true
+			}
+		case SyncCopy, SyncUpdate:
+			var fi fs.FileInfo
+			if fi, err = os.Lstat(from); err == nil && fi.IsDir() {
+				dirs[op.Path] = true
+			} else if err == nil {
+				if err = os.MkdirAll(filepath.Dir(to), 0o755); err == nil {
+					_, err = CopyTree(from, to, copyOpts)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	sorted := make([]string, 0, len(dirs))
+	for rel := range dirs {
+		sorted = append(sorted, rel)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(sorted)))
+	for _, rel := range sorted {
+		fi, err := os.Stat(filepath.Join(src, filepath.FromSlash(rel)))
+		if isNotExist(err) || err == nil && !fi.IsDir() {
+			continue // the parent of a deleted entry that is gone too
+		} else if err != nil {
+			return err
+		}
+		to := filepath.Join(dst, filepath.FromSlash(rel))
+		if err := os.Chmod(to, fi.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := os.Chtimes(to, fi.ModTime(), fi.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSync(t *testing.T) {
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "mirror")
+	writeTree(t, src, map[string]string{
+		"same.txt":     "same",
+		"changed.txt":  "new content",
+		"dir/deep.txt": "deep",
+		"tmp/x.tmp":    "excluded",
+	})
+	os.Symlink("same.txt", filepath.Join(src, "link"))
+	opts := SyncOptions{Delete: true, Filter: Filter{Exclude: []string{"tmp"}}}
+
+	plan, err := Sync(src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "dir", "deep.txt")); got != "deep" {
+		t.Errorf("dir/deep.txt = %q", got)
+	}
+	if ok, _ := LExists(filepath.Join(dst, "tmp")); ok {
+		t.Error("excluded directory was copied")
+	}
+	if len(plan.Ops) != 5 {
+		t.Errorf("first plan:\n%s", plan)
+	}
+
+	// Change the source and the destination, then plan again.
+	old := time.Now().Add(-time.Hour)
+	writeTree(t, src, map[string]string{"changed.txt": "newer content", "added.txt": "added"})
+	os.Chtimes(filepath.Join(src, "changed.txt"), old, old)
+	os.Remove(filepath.Join(src, "dir", "deep.txt"))
+	writeTree(t, dst, map[string]string{"extra/file": "extra", "tmp/keep": "excluded"})
+
+	plan, err = Sync(src, dst, SyncOptions{Delete: true, DryRun: true, Filter: opts.Filter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "delete dir/deep.txt\ndelete extra\nupdate dir\ncopy added.txt\nupdate changed.txt\n"
+	if got := plan.String(); got != want {
+		t.Errorf("plan =\n%s\nwant\n%s", got, want)
+	}
+	if ok, _ := Exists(filepath.Join(dst, "extra", "file")); !ok {
+		t.Fatal("dry run deleted a file")
+	}
+
+	if _, err := Sync(src, dst, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "changed.txt")); got != "newer content" {
+		t.Errorf("changed.txt = %q", got)
+	}
+	if ok, _ := LExists(filepath.Join(dst, "extra")); ok {
+		t.Error("extraneous directory was not deleted")
+	}
+	if ok, _ := Exists(filepath.Join(dst, "tmp", "keep")); !ok {
+		t.Error("excluded destination entry was deleted")
+	}
+	if plan, err := Sync(src, dst, opts); err != nil || len(plan.Ops) != 0 {
+		t.Errorf("sync of a mirror: %v\n%s", err, plan)
+	}
+}
+
+func TestSyncChecksum(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeTree(t, src, map[string]string{"f": "aaaa"})
+	writeTree(t, dst, map[string]string{"f": "bbbb"})
+	mtime := time.Unix(1600000000, 0)
+	os.Chtimes(filepath.Join(src, "f"), mtime, mtime)
+	os.Chtimes(filepath.Join(dst, "f"), mtime, mtime)
+
+	if plan, _ := Sync(src, dst, SyncOptions{DryRun: true}); len(plan.Ops) != 0 {
+		t.Errorf("size and mtime sync planned:\n%s", plan)
+	}
+	if plan, _ := Sync(src, dst, SyncOptions{DryRun: true, Checksum: true}); plan.String() != "update f\n" {
+		t.Errorf("checksum sync planned:\n%s", plan)
+	}
+}
+
+func TestSyncDirectories(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writeTree(t, src, map[string]string{"a/b/f.txt": "f", "a/skip.bin": "x"})
+	opts := SyncOptions{Filter: Filter{Include: []string{"*.txt"}}}
+	if _, err := Sync(src, dst, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dst, "a", "b", "f.txt")); got != "f" {
+		t.Errorf("a/b/f.txt = %q", got)
+	}
+	if ok, _ := LExists(filepath.Join(dst, "a", "skip.bin")); ok {
+		t.Error("a file not matching Include was copied")
+	}
+
+	// Mode and mtime changes of existing directories are mirrored.
+	mtime := time.Unix(1600000000, 0)
+	os.Chmod(filepath.Join(src, "a", "b"), 0o700)
+	os.Chtimes(filepath.Join(src, "a"), mtime, mtime)
+	plan, err := Sync(src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "update a\nupdate a/b\n"; plan.String() != want {
+		t.Errorf("plan =\n%s\nwant\n%s", plan, want)
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "a", "b")); err != nil || fi.Mode().Perm() != 0o700 {
+		t.Errorf("a/b: %v, %v", fi, err)
+	}
+	if fi, err := os.Stat(filepath.Join(dst, "a")); err != nil || !fi.ModTime().Equal(mtime) {
+		t.Errorf("a: %v, %v", fi, err)
+	}
+	if plan, err := Sync(src, dst, opts); err != nil || len(plan.Ops) != 0 {
+		t.Errorf("sync of a mirror: %v\n%s", err, plan)
+	}
+}
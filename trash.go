@@ -0,0 +1,144 @@
+package gofilepath
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// moveToTrash moves the absolute path abs to the trash as described by
+// the freedesktop.org Trash specification and returns its new location.
+// Files on the home filesystem go to $XDG_DATA_HOME/Trash; files on other
+// filesystems go to $topdir/.Trash/$uid if the administrator created a
+// valid .Trash, else to $topdir/.Trash-$uid.
+func moveToTrash(abs string) (string, error) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return "", errors.ErrUnsupported
+	}
+	fi, err := os.Lstat(abs)
+	if err != nil {
+		return "", err
+	}
+	dev, ok := deviceOf(fi)
+	if !ok {
+		return "", errors.ErrUnsupported
+	}
+
+	env := ExpandOptions{}
+	data, _ := env.lookupEnv("XDG_DATA_HOME")
+	if !filepath.IsAbs(data) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	trash := filepath.Join(data, "Trash")
+	infoPath := abs // absolute in the home trash
+
+	homeDev, ok := nearestDevice(trash)
+	if !ok || homeDev != dev {
+		top := topDir(abs, dev)
+		uid := strconv.Itoa(os.Getuid())
+		trash = filepath.Join(top, ".Trash-"+uid)
+		if shared := filepath.Join(top, ".Trash"); validSharedTrash(shared) {
+			trash = filepath.Join(shared, uid)
+		}
+		// Relative to the top directory in a top directory trash.
+		infoPath, _ = filepath.Rel(top, abs)
+	}
+	for _, dir := range []string{filepath.Join(trash, "files"), filepath.Join(trash, "info")} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return "", err
+		}
+	}
+
+	// Reserve a name by creating its .trashinfo file exclusively, then
+	// move the file under the same name.
+	base := filepath.Base(abs)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: filepath.ToSlash(infoPath)}).EscapedPath(),
+		time.Now().Format("2006-01-02T15:04:05"))
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d%s", stem, i, ext)
+		}
+		infoFile := filepath.Join(trash, "info", name+".trashinfo")
+		f, err := os.OpenFile(infoFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		_, err = f.WriteString(info)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		dst := filepath.Join(trash, "files", name)
+		if err == nil {
+			err = os.Rename(abs, dst)
+		}
+		if err != nil {
+			os.Remove(infoFile)
+			return "", err
+		}
+		return dst, nil
+	}
+}
+
+func deviceOf(fi fs.FileInfo) (uint64, bool) {
+	id, _, _, ok := fileIdentity(fi)
+	return id.dev, ok
+}
+
+// nearestDevice returns the device of path or of its closest existing
+// parent.
+func nearestDevice(path string) (uint64, bool) {
+	for {
+		if fi, err := os.Stat(path); err == nil {
+			return deviceOf(fi)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return 0, false
+		}
+		path = parent
+	}
+}
+
+// topDir returns the mount point of the filesystem dev that abs is on: its
+// highest parent on the same device.
+func topDir(abs string, dev uint64) string {
+	top := filepath.Dir(abs)
+	for {
+		parent := filepath.Dir(top)
+		if parent == top {
+			return top
+		}
+		fi, err := os.Stat(parent)
+		if err != nil {
+			return top
+		}
+		if d, ok := deviceOf(fi); !ok || d != dev {
+			return top
+		}
+		top = parent
+	}
+}
+
+// validSharedTrash reports whether dir is a $topdir/.Trash usable per the
+// specification: a real directory with the sticky bit set.
+func validSharedTrash(dir string) bool {
+	fi, err := os.Lstat(dir)
+	return err == nil && fi.IsDir() && fi.Mode()&fs.ModeSticky != 0
+}
@@ -16,8 +16,8 @@ func DirIsEmpty(name string) (bool, error) {
 	}
 	defer f.Close()
 
-	// read in ONLY one file
-	_, err = f.Readdir(1)
+	// read in ONLY one name
+	_, err = f.Readdirnames(1)
 
 	// and if the file is EOF... well, the dir is empty.
 	if err == io.EOF {
@@ -121,11 +121,8 @@ func CountPathSeparator(namepath string) int {
 }
 
 func PathIsUnixSocket(addr string) bool {
-	fileInfo, err := os.Stat(addr)
-	if err != nil {
-		return false // Assume addr is a TCP socket address
-	}
-	return fileInfo.Mode()&os.ModeSocket != 0
+	pi, _ := Inspect(addr) // on error assume addr is a TCP socket address
+	return pi.ResolvedKind() == KindSocket
 }
 
 func PathIsChildOf(path, parentDir string) (b bool, err error) {
@@ -151,12 +148,12 @@ func PathIsChildOf(path, parentDir string) (b bool, err error) {
 	if absPath == absParentDir {
 		return false, nil
 	}
-	return strings.HasPrefix(absPath, absParentDir), nil
+	return contains(absParentDir, absPath), nil
 }
 
 // This function returns the first existing path in the given list of paths.
 func FirstExistPath(path string) string {
-	for _, v := range filepath.SplitList(path) {
+	for _, v := range ParsePathList(path, FlavorHost).Entries() {
 		if _, err := os.Stat(v); err == nil {
 			return v
 		}
@@ -165,7 +162,7 @@ func FirstExistPath(path string) string {
 }
 
 func GetPathInPaths(pathToCheck, paths string) string {
-	for _, p := range strings.Split(paths, string(os.PathListSeparator)) {
+	for _, p := range ParsePathList(paths, FlavorHost).Entries() {
 		if b, _ := PathIsChildOf(pathToCheck, p); b {
 			return p
 		}
@@ -173,9 +170,10 @@ func GetPathInPaths(pathToCheck, paths string) string {
 	return ""
 }
 
+// PathHasSubpath reports whether subpath exists below any directory of the
+// PATH-style list PATH, split with the OS list separator.
 func PathHasSubpath(subpath, PATH string) bool {
-	pashListSeparator := GetPathSeparator(PATH)
-	for _, val := range strings.Split(PATH, string(pashListSeparator)) {
+	for _, val := range SplitListFlavor(PATH, FlavorHost) {
 		if _, err := os.Stat(filepath.Join(val, subpath)); err == nil {
 			return true
 		}
@@ -255,13 +253,20 @@ func JoinSlash(elem ...string) string {
 
 // RelSlash returns a relative path from basepath to targpath, handling both
 // separators in input. Output uses '/'.
+// If either path has a Windows drive or UNC share, Windows rules apply on
+// every OS (see RelFlavor): different volumes return ErrDifferentVolume and
+// names are compared case-insensitively.
 //
 //	RelSlash("C:\\Users\\home", "C:\\Users\\home\\docs\\file.txt") → "docs/file.txt"
 func RelSlash(basepath, targpath string) (string, error) {
-	rel, err := filepath.Rel(
-		filepath.FromSlash(NormalizeSeparators(basepath)),
-		filepath.FromSlash(NormalizeSeparators(targpath)),
-	)
+	basepath, targpath = NormalizeSeparators(basepath), NormalizeSeparators(targpath)
+	bv, _ := SplitVolumeFlavor(basepath, FlavorWindows)
+	tv, _ := SplitVolumeFlavor(targpath, FlavorWindows)
+	if bv != "" || tv != "" {
+		rel, err := RelFlavor(basepath, targpath, FlavorWindows)
+		return NormalizeSeparators(rel), err
+	}
+	rel, err := filepath.Rel(filepath.FromSlash(basepath), filepath.FromSlash(targpath))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,95 @@
+package gofilepath
+
+// DriveType classifies a volume. The values match the Windows
+// GetDriveType constants.
+type DriveType int
+
+const (
+	DriveUnknown   DriveType = iota // type cannot be determined
+	DriveNoRootDir                  // root path is invalid
+	DriveRemovable                  // floppy, USB stick, card reader
+	DriveFixed                      // hard disk or SSD
+	DriveRemote                     // network drive
+	DriveCDROM                      // optical drive
+	DriveRAMDisk                    // RAM disk
+)
+
+var driveTypeNames = [...]string{
+	DriveUnknown:   "unknown",
+	DriveNoRootDir: "no-root-dir",
+	DriveRemovable: "removable",
+	DriveFixed:     "fixed",
+	DriveRemote:    "remote",
+	DriveCDROM:     "cdrom",
+	DriveRAMDisk:   "ramdisk",
+}
+
+func (t DriveType) String() string {
+	if t >= 0 && int(t) < len(driveTypeNames) {
+		return driveTypeNames[t]
+	}
+	return "unknown"
+}
+
+// Volume describes a mounted volume.
+type Volume struct {
+	Root       string    // canonical root path, e.g. `C:\` or "/mnt/data"
+	Label      string    // volume label, empty if unknown
+	FileSystem string    // e.g. "NTFS", "ext4"
+	Type       DriveType // kind of drive
+	TotalBytes uint64    // capacity
+	FreeBytes  uint64    // free space
+	AvailBytes uint64    // free space available to the calling user
+}
+
+// Volumes returns the volumes of the system: drive letters on Windows and
+// mounts with backing storage on Linux. Information that cannot be read for
+// a volume (e.g. an empty card reader) is left zero.
+func Volumes() ([]Volume, error) {
+	return volumes()
+}
+
+// volumeAPI is the subset of the Win32 volume API used by Volumes, so the
+// result shaping can be tested on any OS.
+type volumeAPI interface {
+	LogicalDrives() (uint32, error)
+	DriveType(root string) uint32
+	VolumeInformation(root string) (label, fileSystem string, err error)
+	DiskFreeSpace(root string) (avail, total, free uint64, err error)
+}
+
+func bitsToDrives(bitMap uint32) (drives []string) {
+	availableDrives := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
+
+	for i := range availableDrives {
+		if bitMap&1 == 1 {
+			drives = append(drives, availableDrives[i])
+		}
+		bitMap >>= 1
+	}
+	return
+}
+
+// volumesFrom builds the volume list from the drive bitmask of api.
+func volumesFrom(api volumeAPI) ([]Volume, error) {
+	bits, err := api.LogicalDrives()
+	if err != nil {
+		return nil, err
+	}
+	vols := []Volume{}
+	for _, letter := range bitsToDrives(bits) {
+		v := Volume{Root: letter + `:\`}
+		v.Type = DriveType(api.DriveType(v.Root))
+		if v.Type == DriveNoRootDir {
+			continue
+		}
+		if label, fsname, err := api.VolumeInformation(v.Root); err == nil {
+			v.Label, v.FileSystem = label, fsname
+		}
+		if avail, total, free, err := api.DiskFreeSpace(v.Root); err == nil {
+			v.AvailBytes, v.TotalBytes, v.FreeBytes = avail, total, free
+		}
+		vols = append(vols, v)
+	}
+	return vols, nil
+}
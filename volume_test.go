@@ -0,0 +1,56 @@
+package gofilepath
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeVolumeAPI struct {
+	bits  uint32
+	types map[string]uint32
+}
+
+func (f fakeVolumeAPI) LogicalDrives() (uint32, error) { return f.bits, nil }
+func (f fakeVolumeAPI) DriveType(root string) uint32   { return f.types[root] }
+
+func (f fakeVolumeAPI) VolumeInformation(root string) (string, string, error) {
+	if root == `D:\` {
+		return "", "", errors.New("device not ready")
+	}
+	return "System", "NTFS", nil
+}
+
+func (f fakeVolumeAPI) DiskFreeSpace(root string) (uint64, uint64, uint64, error) {
+	if root == `D:\` {
+		return 0, 0, 0, errors.New("device not ready")
+	}
+	return 10, 100, 20, nil
+}
+
+func TestBitsToDrives(t *testing.T) {
+	if got, want := bitsToDrives(0b1101), []string{"A", "C", "D"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bitsToDrives(0b1101) = %q, want %q", got, want)
+	}
+	if got := bitsToDrives(0); len(got) != 0 {
+		t.Errorf("bitsToDrives(0) = %q, want none", got)
+	}
+}
+
+func TestVolumesFrom(t *testing.T) {
+	api := fakeVolumeAPI{
+		bits:  1<<2 | 1<<3 | 1<<25, // C, D, Z
+		types: map[string]uint32{`C:\`: 3, `D:\`: 5, `Z:\`: 1},
+	}
+	vols, err := volumesFrom(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Volume{
+		{Root: `C:\`, Label: "System", FileSystem: "NTFS", Type: DriveFixed, TotalBytes: 100, FreeBytes: 20, AvailBytes: 10},
+		{Root: `D:\`, Type: DriveCDROM},
+	}
+	if !reflect.DeepEqual(vols, want) {
+		t.Errorf("volumesFrom = %+v, want %+v", vols, want)
+	}
+}
@@ -0,0 +1,239 @@
+package gofilepath
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchOp is a set of changes reported for a path.
+type WatchOp uint32
+
+const (
+	WatchCreate WatchOp = 1 << iota
+	WatchWrite
+	WatchRemove
+	WatchRename // moved away; the new name is reported as WatchCreate
+	WatchChmod
+)
+
+func (op WatchOp) String() string {
+	names := []string{}
+	for i, n := range []string{"CREATE", "WRITE", "REMOVE", "RENAME", "CHMOD"} {
+		if op&(1<<i) != 0 {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return "NONE"
+	}
+	return strings.Join(names, "|")
+}
+
+// WatchEvent is a coalesced change of one path.
+type WatchEvent struct {
+	Path string  // path of the entry, joined to the watched root
+	Op   WatchOp // all changes seen during the debounce interval
+}
+
+// WatchOptions controls Watch.
+type WatchOptions struct {
+	// Filter selects the reported paths, matched against the path relative
+	// to root. Directories matching Filter.Exclude are not watched.
+	Filter Filter
+
+	// MaxDepth limits how deep below root changes are reported, like the
+	// maxdeep argument of the finders. Negative means unlimited; zero
+	// watches only the entries directly in root.
+	MaxDepth int
+
+	// Debounce is how long events are collected and merged per path before
+	// they are delivered. Zero means 100ms.
+	Debounce time.Duration
+
+	// Poll forces the polling implementation, which works on any
+	// filesystem, including NFS and FUSE where inotify sees no remote
+	// changes. It is also used when no native watcher is available.
+	Poll bool
+
+	// PollInterval is the scan interval of the polling implementation.
+	// Zero means one second.
+	PollInterval time.Duration
+}
+
+// Watcher delivers filesystem changes below a root directory.
+type Watcher struct {
+	// Events receives coalesced changes. It is closed when the watcher
+	// stops.
+	Events <-chan WatchEvent
+
+	// Errors receives non-fatal errors, e.g. a directory that could not be
+	// watched or an event queue overflow. It is closed when the watcher
+	// stops.
+	Errors <-chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close stops the watcher and waits until Events and Errors are closed.
+func (w *Watcher) Close() error {
+	w.cancel()
+	<-w.done
+	return nil
+}
+
+// watchBackend produces raw events on raw until ctx is done.
+type watchBackend interface {
+	run(ctx context.Context, raw chan<- WatchEvent, errs chan<- error)
+}
+
+// watchScope decides which paths below root are watched and reported.
+type watchScope struct {
+	root string
+	opts WatchOptions
+}
+
+func (s watchScope) rel(path string) (string, int, bool) {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || rel == ".." {
+		return "", 0, false
+	}
+	return rel, strings.Count(rel, string(filepath.Separator)), true
+}
+
+// report reports whether changes of path are delivered.
+func (s watchScope) report(path string) bool {
+	rel, depth, ok := s.rel(path)
+	if !ok || (s.opts.MaxDepth >= 0 && depth > s.opts.MaxDepth) {
+		return false
+	}
+	return s.opts.Filter.Match(rel)
+}
+
+// descend reports whether the directory path is watched.
+func (s watchScope) descend(path string) bool {
+	if path == s.root {
+		return true
+	}
+	rel, depth, ok := s.rel(path)
+	if !ok || (s.opts.MaxDepth >= 0 && depth >= s.opts.MaxDepth) {
+		return false
+	}
+	return !s.opts.Filter.Excluded(rel)
+}
+
+// Watch watches root recursively until ctx is done or Close is called.
+// New subdirectories are watched as they appear. On Linux inotify is used
+// unless opts.Poll is set; elsewhere, or if inotify cannot be initialized,
+// the tree is polled.
+//
+// Events for the same path within opts.Debounce are merged into one
+// WatchEvent. The channels must be drained by the caller.
+func Watch(ctx context.Context, root string, opts WatchOptions) (*Watcher, error) {
+	root, err := filepath.Abs(FromSlash(root))
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := IsDir(root); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("watch: " + root + " is not a directory")
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = 100 * time.Millisecond
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	scope := watchScope{root: root, opts: opts}
+	var backend watchBackend
+	if !opts.Poll {
+		backend, err = newNativeWatcher(scope)
+	}
+	if opts.Poll || err != nil {
+		backend = newPollWatcher(scope)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := make(chan WatchEvent, 64)
+	errs := make(chan error, 16)
+	w := &Watcher{Events: events, Errors: errs, cancel: cancel, done: make(chan struct{})}
+	raw := make(chan WatchEvent, 256)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(raw)
+		backend.run(ctx, raw, errs)
+	}()
+	go func() {
+		coalesce(ctx, raw, events, opts.Debounce, scope)
+		wg.Wait()
+		close(events)
+		close(errs)
+		close(w.done)
+	}()
+	return w, nil
+}
+
+// coalesce merges raw events per path and delivers them after debounce.
+func coalesce(ctx context.Context, raw <-chan WatchEvent, out chan<- WatchEvent, debounce time.Duration, scope watchScope) {
+	pending := map[string]WatchOp{}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	flush := func() bool {
+		paths := make([]string, 0, len(pending))
+		for p := range pending {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			select {
+			case out <- WatchEvent{Path: p, Op: pending[p]}:
+			case <-ctx.Done():
+				return false
+			}
+			delete(pending, p)
+		}
+		return true
+	}
+	for {
+		select {
+		case ev, ok := <-raw:
+			if !ok {
+				return
+			}
+			if !scope.report(ev.Path) {
+				continue
+			}
+			if len(pending) == 0 {
+				timer.Reset(debounce)
+			}
+			pending[ev.Path] |= ev.Op
+		case <-timer.C:
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			// Drain the backend so it can exit.
+			for range raw {
+			}
+			return
+		}
+	}
+}
+
+// sendErr delivers err without blocking the backend if nobody listens.
+func sendErr(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,182 @@
+package gofilepath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ATTRIB | unix.IN_DELETE_SELF |
+	unix.IN_MOVE_SELF | unix.IN_ONLYDIR | unix.IN_DONT_FOLLOW
+
+// inotifyWatcher watches a tree with one inotify watch per directory.
+type inotifyWatcher struct {
+	scope watchScope
+	f     *os.File // non-blocking inotify fd, so Close unblocks Read
+	fd    int
+	dirs  map[int]string // watch descriptor -> directory
+	wds   map[string]int // directory -> watch descriptor
+}
+
+func newNativeWatcher(scope watchScope) (watchBackend, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	w := &inotifyWatcher{
+		scope: scope,
+		f:     os.NewFile(uintptr(fd), "inotify"),
+		fd:    fd,
+		dirs:  map[int]string{},
+		wds:   map[string]int{},
+	}
+	if err := w.addTree(scope.root, nil, nil); err != nil {
+		w.f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// addTree watches dir and its subdirectories. When raw is not nil, the
+// entries found are reported as created: they may have appeared before the
+// watch was in place.
+func (w *inotifyWatcher) addTree(dir string, raw func(WatchEvent), errs func(error)) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			if errs != nil {
+				errs(err)
+			}
+			return nil
+		}
+		if path != dir && raw != nil {
+			raw(WatchEvent{Path: path, Op: WatchCreate})
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if !w.scope.descend(path) {
+			return filepath.SkipDir
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			if path == dir {
+				return &os.PathError{Op: "inotify_add_watch", Path: path, Err: err}
+			}
+			if errs != nil {
+				errs(&os.PathError{Op: "inotify_add_watch", Path: path, Err: err})
+			}
+			return filepath.SkipDir
+		}
+		w.dirs[wd] = path
+		w.wds[path] = wd
+		return nil
+	})
+}
+
+func (w *inotifyWatcher) run(ctx context.Context, raw chan<- WatchEvent, errs chan<- error) {
+	go func() {
+		<-ctx.Done()
+		w.f.Close()
+	}()
+	emit := func(ev WatchEvent) {
+		select {
+		case raw <- ev:
+		case <-ctx.Done():
+		}
+	}
+	report := func(err error) { sendErr(errs, err) }
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.f.Read(buf)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, os.ErrClosed) {
+				report(fmt.Errorf("inotify read: %w", err))
+			}
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			ie := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameBytes := buf[off+unix.SizeofInotifyEvent : off+unix.SizeofInotifyEvent+int(ie.Len)]
+			off += unix.SizeofInotifyEvent + int(ie.Len)
+
+			if ie.Mask&unix.IN_Q_OVERFLOW != 0 {
+				report(errors.New("inotify event queue overflow, events were lost"))
+				continue
+			}
+			dir, ok := w.dirs[int(ie.Wd)]
+			if !ok {
+				continue
+			}
+			if ie.Mask&unix.IN_IGNORED != 0 {
+				delete(w.dirs, int(ie.Wd))
+				delete(w.wds, dir)
+				continue
+			}
+			path := dir
+			if name := cString(nameBytes); name != "" {
+				path = filepath.Join(dir, name)
+			}
+
+			var op WatchOp
+			switch {
+			case ie.Mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+				op = WatchCreate
+			case ie.Mask&(unix.IN_DELETE|unix.IN_DELETE_SELF) != 0:
+				op = WatchRemove
+			case ie.Mask&(unix.IN_MOVED_FROM|unix.IN_MOVE_SELF) != 0:
+				op = WatchRename
+			case ie.Mask&(unix.IN_MODIFY|unix.IN_CLOSE_WRITE) != 0:
+				op = WatchWrite
+			case ie.Mask&unix.IN_ATTRIB != 0:
+				op = WatchChmod
+			}
+			if ie.Mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0 && path != w.scope.root {
+				continue // already reported by the parent directory
+			}
+			emit(WatchEvent{Path: path, Op: op})
+
+			if ie.Mask&unix.IN_ISDIR != 0 {
+				switch {
+				case op == WatchCreate:
+					if err := w.addTree(path, emit, report); err != nil {
+						report(err)
+					}
+				case op == WatchRename || op == WatchRemove:
+					w.forget(path)
+				}
+			}
+		}
+	}
+}
+
+// forget drops the watches of dir and its subdirectories after it was
+// moved away or removed.
+func (w *inotifyWatcher) forget(dir string) {
+	for path, wd := range w.wds {
+		if path == dir || pathWithin(path, dir) {
+			unix.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.wds, path)
+			delete(w.dirs, wd)
+		}
+	}
+}
+
+// cString returns the NUL-terminated string at the start of b.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package gofilepath
+
+import "errors"
+
+func newNativeWatcher(scope watchScope) (watchBackend, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,95 @@
+package gofilepath
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// pollState is what the polling watcher compares between scans.
+type pollState struct {
+	size  int64
+	mtime time.Time
+	mode  fs.FileMode
+}
+
+// pollWatcher detects changes by scanning the tree every PollInterval.
+type pollWatcher struct {
+	scope watchScope
+	prev  map[string]pollState
+}
+
+func newPollWatcher(scope watchScope) watchBackend {
+	w := &pollWatcher{scope: scope}
+	w.prev = w.scan(nil)
+	return w
+}
+
+// scan records the state of every watched entry below root.
+func (w *pollWatcher) scan(errs chan<- error) map[string]pollState {
+	m := map[string]pollState{}
+	filepath.WalkDir(w.scope.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errs != nil && !isNotExist(err) {
+				sendErr(errs, err)
+			}
+			return nil
+		}
+		if path != w.scope.root {
+			if info, err := d.Info(); err == nil {
+				m[path] = pollState{size: info.Size(), mtime: info.ModTime(), mode: info.Mode()}
+			}
+		}
+		if d.IsDir() && !w.scope.descend(path) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return m
+}
+
+func (w *pollWatcher) run(ctx context.Context, raw chan<- WatchEvent, errs chan<- error) {
+	ticker := time.NewTicker(w.scope.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur := w.scan(errs)
+		for _, ev := range diffPollStates(w.prev, cur) {
+			select {
+			case raw <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+		w.prev = cur
+	}
+}
+
+// diffPollStates returns the events that turn prev into cur.
+func diffPollStates(prev, cur map[string]pollState) []WatchEvent {
+	var events []WatchEvent
+	for path, c := range cur {
+		p, ok := prev[path]
+		switch {
+		case !ok || p.mode.Type() != c.mode.Type():
+			events = append(events, WatchEvent{Path: path, Op: WatchCreate})
+		case p.size != c.size || !p.mtime.Equal(c.mtime):
+			if c.mode.IsRegular() {
+				events = append(events, WatchEvent{Path: path, Op: WatchWrite})
+			}
+		case p.mode != c.mode:
+			events = append(events, WatchEvent{Path: path, Op: WatchChmod})
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			events = append(events, WatchEvent{Path: path, Op: WatchRemove})
+		}
+	}
+	return events
+}
@@ -0,0 +1,103 @@
+//go:build !windows
+// +build !windows
+
+package gofilepath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitEvent returns the first event for path, failing after a timeout.
+func waitEvent(t *testing.T, w *Watcher, path string, op WatchOp) WatchEvent {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-w.Events:
+			if ev.Path == path && ev.Op&op != 0 {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("no %v event for %s", op, path)
+			return WatchEvent{}
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	for _, poll := range []bool{false, true} {
+		name := "native"
+		if poll {
+			name = "poll"
+		}
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.Mkdir(filepath.Join(root, "skip"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			w, err := Watch(context.Background(), root, WatchOptions{
+				Filter:       Filter{Include: []string{"*.txt"}, Exclude: []string{"skip"}},
+				MaxDepth:     -1,
+				Debounce:     20 * time.Millisecond,
+				Poll:         poll,
+				PollInterval: 50 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			a := filepath.Join(root, "a.txt")
+			os.WriteFile(filepath.Join(root, "skip", "b.txt"), []byte("x"), 0o644)
+			os.WriteFile(filepath.Join(root, "ignored.log"), []byte("x"), 0o644)
+			if err := os.WriteFile(a, []byte("1"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, a, WatchCreate)
+
+			// A new subdirectory is watched, including what is created in it
+			// before its watch is in place.
+			sub := filepath.Join(root, "sub", "deeper")
+			if err := os.MkdirAll(sub, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			c := filepath.Join(sub, "c.txt")
+			if err := os.WriteFile(c, []byte("1"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, c, WatchCreate)
+
+			time.Sleep(20 * time.Millisecond) // let the poller see a new mtime
+			if err := os.WriteFile(c, []byte("22"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, c, WatchWrite)
+
+			if err := os.Remove(a); err != nil {
+				t.Fatal(err)
+			}
+			waitEvent(t, w, a, WatchRemove)
+
+			w.Close()
+			for ev := range w.Events {
+				switch filepath.Base(ev.Path) {
+				case "b.txt", "ignored.log":
+					t.Errorf("filtered path reported: %v", ev)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchOpString(t *testing.T) {
+	if got := (WatchCreate | WatchWrite).String(); got != "CREATE|WRITE" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := WatchOp(0).String(); got != "NONE" {
+		t.Errorf("String() = %q", got)
+	}
+}